@@ -11,19 +11,23 @@ import (
 )
 
 const (
-	AttributeKeyConnectionID         = types.AttributeKeyConnectionID
-	AttributeKeyCounterpartyClientID = types.AttributeKeyCounterpartyClientID
-	SubModuleName                    = types.SubModuleName
-	StoreKey                         = types.StoreKey
-	RouterKey                        = types.RouterKey
-	QuerierRoute                     = types.QuerierRoute
-	QueryAllConnections              = types.QueryAllConnections
-	QueryAllClientConnections        = types.QueryAllClientConnections
-	QueryClientConnections           = types.QueryClientConnections
-	UNINITIALIZED                    = types.UNINITIALIZED
-	INIT                             = types.INIT
-	TRYOPEN                          = types.TRYOPEN
-	OPEN                             = types.OPEN
+	AttributeKeyConnectionID             = types.AttributeKeyConnectionID
+	AttributeKeyClientID                 = types.AttributeKeyClientID
+	AttributeKeyCounterpartyClientID     = types.AttributeKeyCounterpartyClientID
+	AttributeKeyCounterpartyConnectionID = types.AttributeKeyCounterpartyConnectionID
+	ProofKindConnection                  = types.ProofKindConnection
+	ProofKindConsensus                   = types.ProofKindConsensus
+	SubModuleName                        = types.SubModuleName
+	StoreKey                             = types.StoreKey
+	RouterKey                            = types.RouterKey
+	QuerierRoute                         = types.QuerierRoute
+	QueryAllConnections                  = types.QueryAllConnections
+	QueryAllClientConnections            = types.QueryAllClientConnections
+	QueryClientConnections               = types.QueryClientConnections
+	UNINITIALIZED                        = types.UNINITIALIZED
+	INIT                                 = types.INIT
+	TRYOPEN                              = types.TRYOPEN
+	OPEN                                 = types.OPEN
 )
 
 var (
@@ -42,6 +46,13 @@ var (
 	ErrConnectionPath                = types.ErrConnectionPath
 	ErrInvalidConnectionState        = types.ErrInvalidConnectionState
 	ErrInvalidCounterparty           = types.ErrInvalidCounterparty
+	ErrInvalidConnection             = types.ErrInvalidConnection
+	ErrHandshakeInProgress           = types.ErrHandshakeInProgress
+	ErrProofHeightTooHigh            = types.ErrProofHeightTooHigh
+	ErrClientTypeNotAllowed          = types.ErrClientTypeNotAllowed
+	ErrProofHeightTooOld             = types.ErrProofHeightTooOld
+	ErrSelfConnection                = types.ErrSelfConnection
+	ErrConnectionMetadataNotFound    = types.ErrConnectionMetadataNotFound
 	NewMsgConnectionOpenInit         = types.NewMsgConnectionOpenInit
 	NewMsgConnectionOpenTry          = types.NewMsgConnectionOpenTry
 	NewMsgConnectionOpenAck          = types.NewMsgConnectionOpenAck
@@ -55,6 +66,10 @@ var (
 	NewConnectionPaths               = types.NewConnectionPaths
 	DefaultGenesisState              = types.DefaultGenesisState
 	NewGenesisState                  = types.NewGenesisState
+	NewHandshakeLog                  = types.NewHandshakeLog
+	RequiredProofs                   = types.RequiredProofs
+	NewConnectionSnapshot            = types.NewConnectionSnapshot
+	NewHandshakeMeta                 = types.NewHandshakeMeta
 
 	// variable aliases
 	SubModuleCdc                   = types.SubModuleCdc
@@ -80,4 +95,7 @@ type (
 	QueryClientConnectionsParams = types.QueryClientConnectionsParams
 	GenesisState                 = types.GenesisState
 	Paths                        = types.ConnectionPaths
+	HandshakeLog                 = types.HandshakeLog
+	ConnectionSnapshot           = types.ConnectionSnapshot
+	HandshakeMeta                = types.HandshakeMeta
 )
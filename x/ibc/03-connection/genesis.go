@@ -16,6 +16,10 @@ func InitGenesis(ctx sdk.Context, k Keeper, gs GenesisState) {
 }
 
 // ExportGenesis returns the ibc connection submodule's exported genesis.
+//
+// NOTE: the metadata of the handshake messages that created the connections is
+// not part of the genesis state and is lost on export. Use ExportConnections to
+// preserve it.
 func ExportGenesis(ctx sdk.Context, k Keeper) GenesisState {
 	return GenesisState{
 		Connections:           k.GetAllConnections(ctx),
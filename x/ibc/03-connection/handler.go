@@ -13,12 +13,15 @@ func HandleMsgConnectionOpenInit(ctx sdk.Context, k Keeper, msg MsgConnectionOpe
 		return nil, err
 	}
 
+	k.SetConnectionMetadata(ctx, msg.ConnectionID, types.NewHandshakeMeta(msg.Signer, ctx.BlockHeight(), ctx.BlockTime()))
+
 	ctx.EventManager().EmitEvents(sdk.Events{
 		sdk.NewEvent(
 			types.EventTypeConnectionOpenInit,
 			sdk.NewAttribute(types.AttributeKeyConnectionID, msg.ConnectionID),
 			sdk.NewAttribute(types.AttributeKeyClientID, msg.ClientID),
 			sdk.NewAttribute(types.AttributeKeyCounterpartyClientID, msg.Counterparty.ClientID),
+			sdk.NewAttribute(types.AttributeKeyCounterpartyConnectionID, msg.Counterparty.ConnectionID),
 		),
 		sdk.NewEvent(
 			sdk.EventTypeMessage,
@@ -27,12 +30,16 @@ func HandleMsgConnectionOpenInit(ctx sdk.Context, k Keeper, msg MsgConnectionOpe
 	})
 
 	return &sdk.Result{
+		Log:    types.NewHandshakeLog(msg.ConnectionID, types.INIT, 0).String(),
 		Events: ctx.EventManager().Events().ToABCIEvents(),
 	}, nil
 }
 
 // HandleMsgConnectionOpenTry defines the sdk.Handler for MsgConnectionOpenTry
 func HandleMsgConnectionOpenTry(ctx sdk.Context, k Keeper, msg MsgConnectionOpenTry) (*sdk.Result, error) {
+	// on crossing hellos the connection was created by a previous OpenInit
+	_, existed := k.GetConnection(ctx, msg.ConnectionID)
+
 	if err := k.ConnOpenTry(
 		ctx, msg.ConnectionID, msg.Counterparty, msg.ClientID,
 		msg.CounterpartyVersions, msg.ProofInit, msg.ProofConsensus,
@@ -41,12 +48,18 @@ func HandleMsgConnectionOpenTry(ctx sdk.Context, k Keeper, msg MsgConnectionOpen
 		return nil, err
 	}
 
+	if !existed {
+		k.SetConnectionMetadata(ctx, msg.ConnectionID, types.NewHandshakeMeta(msg.Signer, ctx.BlockHeight(), ctx.BlockTime()))
+	}
+	connection, _ := k.GetConnection(ctx, msg.ConnectionID)
+
 	ctx.EventManager().EmitEvents(sdk.Events{
 		sdk.NewEvent(
 			types.EventTypeConnectionOpenTry,
 			sdk.NewAttribute(types.AttributeKeyConnectionID, msg.ConnectionID),
-			sdk.NewAttribute(types.AttributeKeyClientID, msg.ClientID),
-			sdk.NewAttribute(types.AttributeKeyCounterpartyClientID, msg.Counterparty.ClientID),
+			sdk.NewAttribute(types.AttributeKeyClientID, connection.GetClientID()),
+			sdk.NewAttribute(types.AttributeKeyCounterpartyClientID, connection.GetCounterpartyClientID()),
+			sdk.NewAttribute(types.AttributeKeyCounterpartyConnectionID, connection.GetCounterpartyConnectionID()),
 		),
 		sdk.NewEvent(
 			sdk.EventTypeMessage,
@@ -55,6 +68,7 @@ func HandleMsgConnectionOpenTry(ctx sdk.Context, k Keeper, msg MsgConnectionOpen
 	})
 
 	return &sdk.Result{
+		Log:    types.NewHandshakeLog(msg.ConnectionID, types.TRYOPEN, msg.ProofHeight).String(),
 		Events: ctx.EventManager().Events().ToABCIEvents(),
 	}, nil
 }
@@ -68,10 +82,15 @@ func HandleMsgConnectionOpenAck(ctx sdk.Context, k Keeper, msg MsgConnectionOpen
 		return nil, err
 	}
 
+	connection, _ := k.GetConnection(ctx, msg.ConnectionID)
+
 	ctx.EventManager().EmitEvents(sdk.Events{
 		sdk.NewEvent(
-			EventTypeConnectionOpenAck,
-			sdk.NewAttribute(AttributeKeyConnectionID, msg.ConnectionID),
+			types.EventTypeConnectionOpenAck,
+			sdk.NewAttribute(types.AttributeKeyConnectionID, msg.ConnectionID),
+			sdk.NewAttribute(types.AttributeKeyClientID, connection.GetClientID()),
+			sdk.NewAttribute(types.AttributeKeyCounterpartyClientID, connection.GetCounterpartyClientID()),
+			sdk.NewAttribute(types.AttributeKeyCounterpartyConnectionID, connection.GetCounterpartyConnectionID()),
 		),
 		sdk.NewEvent(
 			sdk.EventTypeMessage,
@@ -80,6 +99,7 @@ func HandleMsgConnectionOpenAck(ctx sdk.Context, k Keeper, msg MsgConnectionOpen
 	})
 
 	return &sdk.Result{
+		Log:    types.NewHandshakeLog(msg.ConnectionID, types.OPEN, msg.ProofHeight).String(),
 		Events: ctx.EventManager().Events().ToABCIEvents(),
 	}, nil
 }
@@ -92,10 +112,15 @@ func HandleMsgConnectionOpenConfirm(ctx sdk.Context, k Keeper, msg MsgConnection
 		return nil, err
 	}
 
+	connection, _ := k.GetConnection(ctx, msg.ConnectionID)
+
 	ctx.EventManager().EmitEvents(sdk.Events{
 		sdk.NewEvent(
-			EventTypeConnectionOpenConfirm,
-			sdk.NewAttribute(AttributeKeyConnectionID, msg.ConnectionID),
+			types.EventTypeConnectionOpenConfirm,
+			sdk.NewAttribute(types.AttributeKeyConnectionID, msg.ConnectionID),
+			sdk.NewAttribute(types.AttributeKeyClientID, connection.GetClientID()),
+			sdk.NewAttribute(types.AttributeKeyCounterpartyClientID, connection.GetCounterpartyClientID()),
+			sdk.NewAttribute(types.AttributeKeyCounterpartyConnectionID, connection.GetCounterpartyConnectionID()),
 		),
 		sdk.NewEvent(
 			sdk.EventTypeMessage,
@@ -104,6 +129,7 @@ func HandleMsgConnectionOpenConfirm(ctx sdk.Context, k Keeper, msg MsgConnection
 	})
 
 	return &sdk.Result{
+		Log:    types.NewHandshakeLog(msg.ConnectionID, types.OPEN, msg.ProofHeight).String(),
 		Events: ctx.EventManager().Events().ToABCIEvents(),
 	}, nil
 }
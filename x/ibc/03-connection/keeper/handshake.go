@@ -26,6 +26,18 @@ func (k Keeper) ConnOpenInit(
 		return sdkerrors.Wrap(types.ErrConnectionExists, "cannot initialize connection")
 	}
 
+	if err := k.checkClientTypeAllowed(ctx, clientID); err != nil {
+		return sdkerrors.Wrap(err, "cannot initialize connection")
+	}
+
+	if err := k.checkSelfConnection(ctx, clientID); err != nil {
+		return sdkerrors.Wrap(err, "cannot initialize connection")
+	}
+
+	if k.serializedHandshakes && k.hasHandshakeInProgress(ctx, clientID) {
+		return sdkerrors.Wrapf(types.ErrHandshakeInProgress, "cannot initialize connection for client %s", clientID)
+	}
+
 	// connection defines chain A's ConnectionEnd
 	connection := types.NewConnectionEnd(types.INIT, connectionID, clientID, counterparty, types.GetCompatibleVersions())
 	k.SetConnection(ctx, connectionID, connection)
@@ -59,6 +71,18 @@ func (k Keeper) ConnOpenTry(
 		return sdkerrors.Wrap(sdkerrors.ErrInvalidHeight, "invalid consensus height")
 	}
 
+	if err := k.checkClientTypeAllowed(ctx, clientID); err != nil {
+		return sdkerrors.Wrap(err, "cannot relay connection attempt")
+	}
+
+	if err := k.checkSelfConnection(ctx, clientID); err != nil {
+		return sdkerrors.Wrap(err, "cannot relay connection attempt")
+	}
+
+	if err := k.checkConsensusStateAge(ctx, clientID, proofHeight); err != nil {
+		return sdkerrors.Wrap(err, "cannot relay connection attempt")
+	}
+
 	expectedConsensusState, found := k.clientKeeper.GetSelfConsensusState(ctx, consensusHeight)
 	if !found {
 		return clienttypes.ErrSelfConsensusStateNotFound
@@ -156,6 +180,22 @@ func (k Keeper) ConnOpenAck(
 		)
 	}
 
+	// Check that the proof height is not ahead of the latest consensus state stored
+	// on chainA's client for chainB. The client verification rejects this height too;
+	// this check only returns ErrProofHeightTooHigh before any proof is decoded.
+	clientState, found := k.clientKeeper.GetClientState(ctx, connection.ClientID)
+	if !found {
+		return sdkerrors.Wrap(clienttypes.ErrClientNotFound, connection.ClientID)
+	}
+
+	if proofHeight > clientState.GetLatestHeight() {
+		return sdkerrors.Wrapf(
+			types.ErrProofHeightTooHigh,
+			"proof height %d > latest consensus state height %d for client %s",
+			proofHeight, clientState.GetLatestHeight(), connection.ClientID,
+		)
+	}
+
 	// Retrieve chainA's consensus state at consensusheight
 	expectedConsensusState, found := k.clientKeeper.GetSelfConsensusState(ctx, consensusHeight)
 	if !found {
@@ -167,6 +207,8 @@ func (k Keeper) ConnOpenAck(
 	expectedConnection := types.NewConnectionEnd(types.TRYOPEN, connection.Counterparty.ConnectionID, connection.Counterparty.ClientID, expectedCounterparty, []string{version})
 
 	// Ensure that ChainB stored expected connectionEnd in its state during ConnOpenTry
+	// NOTE: the expected connection is keyed by the counterparty connection identifier
+	// recorded on ConnOpenInit, so ChainB can't switch identifiers mid-handshake
 	if err := k.VerifyConnectionState(
 		ctx, connection, proofHeight, proofTry, connection.Counterparty.ConnectionID,
 		expectedConnection,
@@ -2,9 +2,13 @@ package keeper_test
 
 import (
 	"fmt"
+	"time"
 
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	clientexported "github.com/cosmos/cosmos-sdk/x/ibc/02-client/exported"
 	connection "github.com/cosmos/cosmos-sdk/x/ibc/03-connection"
 	"github.com/cosmos/cosmos-sdk/x/ibc/03-connection/types"
+	localhosttypes "github.com/cosmos/cosmos-sdk/x/ibc/09-localhost/types"
 	commitmenttypes "github.com/cosmos/cosmos-sdk/x/ibc/23-commitment/types"
 	host "github.com/cosmos/cosmos-sdk/x/ibc/24-host"
 )
@@ -46,6 +50,303 @@ func (suite *KeeperTestSuite) TestConnOpenInit() {
 	}
 }
 
+// TestConnOpenInitMetadata - Chain A (ID #1) records the metadata of the
+// OpenInit message that initialized a connection with Chain B (ID #2)
+func (suite *KeeperTestSuite) TestConnOpenInitMetadata() {
+	suite.chainA.CreateClient(suite.chainB)
+
+	k := suite.chainA.App.IBCKeeper.ConnectionKeeper
+	signer := sdk.AccAddress("signer")
+	blockTime := time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC)
+	ctx := suite.chainA.GetContext().WithBlockTime(blockTime)
+
+	_, err := k.GetConnectionMetadata(ctx, testConnectionIDA)
+	suite.Require().True(types.ErrConnectionMetadataNotFound.Is(err))
+
+	msg := connection.NewMsgConnectionOpenInit(
+		testConnectionIDA, testClientIDB, testConnectionIDB, testClientIDA,
+		commitmenttypes.NewMerklePrefix(k.GetCommitmentPrefix().Bytes()), signer,
+	)
+	_, err = connection.HandleMsgConnectionOpenInit(ctx, k, msg)
+	suite.Require().NoError(err)
+
+	// the metadata is readable on later blocks
+	meta, err := k.GetConnectionMetadata(suite.chainA.GetContext(), testConnectionIDA)
+	suite.Require().NoError(err)
+	suite.Require().Equal(connection.NewHandshakeMeta(signer, ctx.BlockHeight(), blockTime), meta)
+
+	// a failed handshake doesn't record metadata
+	msg.ConnectionID = testConnectionID3
+	msg.ClientID = testClientID3
+	_, err = connection.HandleMsgConnectionOpenInit(ctx, k, msg)
+	suite.Require().Error(err)
+
+	_, err = k.GetConnectionMetadata(ctx, testConnectionID3)
+	suite.Require().Error(err)
+}
+
+// TestConnOpenTryMetadata - Chain B (ID #2) records the metadata of the OpenTry
+// message that created a connection with Chain A (ID #1), but keeps the metadata
+// of its own OpenInit on crossing hellos
+func (suite *KeeperTestSuite) TestConnOpenTryMetadata() {
+	initSigner, trySigner := sdk.AccAddress("initsigner"), sdk.AccAddress("trysigner")
+
+	for _, crossing := range []bool{false, true} {
+		suite.SetupTest() // reset
+
+		suite.chainB.CreateClient(suite.chainA)
+		suite.chainA.CreateClient(suite.chainB)
+		suite.chainA.createConnection(testConnectionIDA, testConnectionIDB, testClientIDB, testClientIDA, types.INIT)
+
+		k := suite.chainB.App.IBCKeeper.ConnectionKeeper
+		prefix := commitmenttypes.NewMerklePrefix(k.GetCommitmentPrefix().Bytes())
+		var expMeta connection.HandshakeMeta
+		if crossing {
+			initCtx := suite.chainB.GetContext()
+			_, err := connection.HandleMsgConnectionOpenInit(initCtx, k, connection.NewMsgConnectionOpenInit(
+				testConnectionIDB, testClientIDA, testConnectionIDA, testClientIDB, prefix, initSigner,
+			))
+			suite.Require().NoError(err)
+			expMeta = connection.NewHandshakeMeta(initSigner, initCtx.BlockHeight(), initCtx.BlockTime())
+		}
+
+		suite.chainB.updateClient(suite.chainA)
+		suite.chainA.updateClient(suite.chainB)
+		suite.chainB.updateClient(suite.chainA)
+		suite.chainA.updateClient(suite.chainB)
+		consensusHeight := suite.chainB.Header.GetHeight() - 1
+
+		proofInit, proofHeight := queryProof(suite.chainA, host.KeyConnection(testConnectionIDA))
+		proofConsensus, _ := queryProof(suite.chainA, prefixedClientKey(testClientIDB, host.KeyConsensusState(consensusHeight)))
+
+		tryCtx := suite.chainB.GetContext()
+		_, err := connection.HandleMsgConnectionOpenTry(tryCtx, k, connection.NewMsgConnectionOpenTry(
+			testConnectionIDB, testClientIDA, testConnectionIDA, testClientIDB, prefix,
+			connection.GetCompatibleVersions(), proofInit, proofConsensus, proofHeight+1, consensusHeight, trySigner,
+		))
+		suite.Require().NoError(err, "crossing hellos: %t", crossing)
+		if !crossing {
+			expMeta = connection.NewHandshakeMeta(trySigner, tryCtx.BlockHeight(), tryCtx.BlockTime())
+		}
+
+		meta, err := k.GetConnectionMetadata(suite.chainB.GetContext(), testConnectionIDB)
+		suite.Require().NoError(err)
+		suite.Require().Equal(expMeta, meta, "crossing hellos: %t", crossing)
+	}
+}
+
+// TestConnOpenInitSerialized - Chain A (ID #1) initializes a connection with
+// Chain B (ID #2) while serialized handshakes are enabled
+func (suite *KeeperTestSuite) TestConnOpenInitSerialized() {
+	testCases := []struct {
+		msg        string
+		serialized bool
+		state      types.State
+		expPass    bool
+	}{
+		{"concurrent handshake allowed", false, types.INIT, true},
+		{"previous handshake open", true, types.OPEN, true},
+		{"previous handshake on INIT", true, types.INIT, false},
+		{"previous handshake on TRYOPEN", true, types.TRYOPEN, false},
+	}
+
+	counterparty := connection.NewCounterparty(testClientIDB, testConnectionIDB, commitmenttypes.NewMerklePrefix(suite.chainA.App.IBCKeeper.ConnectionKeeper.GetCommitmentPrefix().Bytes()))
+
+	for i, tc := range testCases {
+		tc := tc
+		i := i
+		suite.Run(fmt.Sprintf("Case %s", tc.msg), func() {
+			suite.SetupTest() // reset
+
+			suite.chainA.App.IBCKeeper.ConnectionKeeper.SetSerializedHandshakes(tc.serialized)
+			suite.chainA.CreateClient(suite.chainB)
+			suite.chainA.createConnection(testConnectionID3, testConnectionIDB, testClientIDB, testClientIDA, tc.state)
+			suite.chainA.App.IBCKeeper.ConnectionKeeper.SetClientConnectionPaths(suite.chainA.GetContext(), testClientIDB, []string{testConnectionID3})
+
+			err := suite.chainA.App.IBCKeeper.ConnectionKeeper.ConnOpenInit(suite.chainA.GetContext(), testConnectionIDA, testClientIDB, counterparty)
+
+			if tc.expPass {
+				suite.Require().NoError(err, "valid test case %d failed: %s", i, tc.msg)
+			} else {
+				suite.Require().Error(err, "invalid test case %d passed: %s", i, tc.msg)
+				suite.Require().True(types.ErrHandshakeInProgress.Is(err), "unexpected error on test case %d: %s", i, err)
+			}
+		})
+	}
+}
+
+// TestConnOpenClientTypeAllowed - Chain A (ID #1) and Chain B (ID #2) only allow
+// connection handshakes on the client types of their allowlist
+func (suite *KeeperTestSuite) TestConnOpenClientTypeAllowed() {
+	testCases := []struct {
+		msg          string
+		allowed      []clientexported.ClientType
+		useLocalhost bool
+		expPass      bool
+	}{
+		{"no allowlist, tendermint client", nil, false, true},
+		{"no allowlist, localhost client", nil, true, true},
+		{"tendermint allowed, tendermint client", []clientexported.ClientType{clientexported.Tendermint}, false, true},
+		{"tendermint allowed, localhost client", []clientexported.ClientType{clientexported.Tendermint}, true, false},
+		{"all allowed, localhost client", []clientexported.ClientType{clientexported.Tendermint, clientexported.Localhost}, true, true},
+	}
+
+	for i, tc := range testCases {
+		tc := tc
+		i := i
+		suite.Run(fmt.Sprintf("Case ConnOpenInit %s", tc.msg), func() {
+			suite.SetupTest() // reset
+
+			clientID := testClientIDB
+			if tc.useLocalhost {
+				clientID = clientexported.ClientTypeLocalHost
+				suite.chainA.App.IBCKeeper.ClientKeeper.SetClientState(
+					suite.chainA.GetContext(), localhosttypes.NewClientState(testClientIDA, suite.chainA.Header.Height),
+				)
+			} else {
+				suite.chainA.CreateClient(suite.chainB)
+			}
+
+			suite.chainA.App.IBCKeeper.ConnectionKeeper.SetAllowedClientTypes(tc.allowed...)
+			counterparty := connection.NewCounterparty(testClientIDA, testConnectionIDB, commitmenttypes.NewMerklePrefix(suite.chainB.App.IBCKeeper.ConnectionKeeper.GetCommitmentPrefix().Bytes()))
+
+			err := suite.chainA.App.IBCKeeper.ConnectionKeeper.ConnOpenInit(suite.chainA.GetContext(), testConnectionIDA, clientID, counterparty)
+
+			if tc.expPass {
+				suite.Require().NoError(err, "valid test case %d failed: %s", i, tc.msg)
+			} else {
+				suite.Require().Error(err, "invalid test case %d passed: %s", i, tc.msg)
+				suite.Require().True(types.ErrClientTypeNotAllowed.Is(err), "unexpected error on test case %d: %s", i, err)
+			}
+		})
+
+		suite.Run(fmt.Sprintf("Case ConnOpenTry %s", tc.msg), func() {
+			suite.SetupTest() // reset
+
+			suite.chainB.CreateClient(suite.chainA)
+			suite.chainA.CreateClient(suite.chainB)
+			suite.chainA.createConnection(testConnectionIDA, testConnectionIDB, testClientIDB, testClientIDA, types.INIT)
+			suite.chainB.updateClient(suite.chainA)
+			suite.chainA.updateClient(suite.chainB)
+			suite.chainB.updateClient(suite.chainA)
+			suite.chainA.updateClient(suite.chainB)
+			consensusHeight := suite.chainB.Header.GetHeight() - 1
+
+			clientID := testClientIDA
+			if tc.useLocalhost {
+				clientID = clientexported.ClientTypeLocalHost
+				suite.chainB.App.IBCKeeper.ClientKeeper.SetClientState(
+					suite.chainB.GetContext(), localhosttypes.NewClientState(testClientIDB, suite.chainB.Header.Height),
+				)
+			}
+
+			suite.chainB.App.IBCKeeper.ConnectionKeeper.SetAllowedClientTypes(tc.allowed...)
+			counterparty := connection.NewCounterparty(
+				testClientIDB, testConnectionIDA, commitmenttypes.NewMerklePrefix(suite.chainB.App.IBCKeeper.ConnectionKeeper.GetCommitmentPrefix().Bytes()),
+			)
+
+			proofInit, proofHeight := queryProof(suite.chainA, host.KeyConnection(testConnectionIDA))
+			proofConsensus, _ := queryProof(suite.chainA, prefixedClientKey(testClientIDB, host.KeyConsensusState(consensusHeight)))
+
+			err := suite.chainB.App.IBCKeeper.ConnectionKeeper.ConnOpenTry(
+				suite.chainB.GetContext(), testConnectionIDB, counterparty, clientID,
+				connection.GetCompatibleVersions(), proofInit, proofConsensus,
+				proofHeight+1, consensusHeight,
+			)
+
+			switch {
+			case !tc.expPass:
+				suite.Require().Error(err, "invalid test case %d passed: %s", i, tc.msg)
+				suite.Require().True(types.ErrClientTypeNotAllowed.Is(err), "unexpected error on test case %d: %s", i, err)
+			case tc.useLocalhost:
+				// the proofs were not generated for the localhost client, which
+				// fails after passing the allowlist
+				suite.Require().Error(err, "invalid test case %d passed: %s", i, tc.msg)
+				suite.Require().False(types.ErrClientTypeNotAllowed.Is(err), "unexpected error on test case %d: %s", i, err)
+			default:
+				suite.Require().NoError(err, "valid test case %d failed: %s", i, tc.msg)
+			}
+		})
+	}
+
+	suite.Run("Case allowlist not aliased", func() {
+		suite.SetupTest() // reset
+
+		suite.chainA.App.IBCKeeper.ClientKeeper.SetClientState(
+			suite.chainA.GetContext(), localhosttypes.NewClientState(testClientIDA, suite.chainA.Header.Height),
+		)
+
+		// changes to the caller's slice don't change the allowlist
+		allowed := []clientexported.ClientType{clientexported.Tendermint}
+		suite.chainA.App.IBCKeeper.ConnectionKeeper.SetAllowedClientTypes(allowed...)
+		allowed[0] = clientexported.Localhost
+
+		counterparty := connection.NewCounterparty(testClientIDA, testConnectionIDB, commitmenttypes.NewMerklePrefix(suite.chainB.App.IBCKeeper.ConnectionKeeper.GetCommitmentPrefix().Bytes()))
+		err := suite.chainA.App.IBCKeeper.ConnectionKeeper.ConnOpenInit(suite.chainA.GetContext(), testConnectionIDA, clientexported.ClientTypeLocalHost, counterparty)
+		suite.Require().True(types.ErrClientTypeNotAllowed.Is(err), "unexpected error: %v", err)
+	})
+}
+
+// TestConnOpenSelfConnection - Chain A (ID #1) rejects the connection handshakes
+// on a client that tracks itself instead of a counterparty chain
+func (suite *KeeperTestSuite) TestConnOpenSelfConnection() {
+	testCases := []struct {
+		msg          string
+		self         bool
+		useLocalhost bool
+	}{
+		{"cross-chain connection", false, false},
+		{"self-connection", true, false},
+		{"localhost connection", false, true},
+	}
+
+	for i, tc := range testCases {
+		tc := tc
+		i := i
+		suite.Run(fmt.Sprintf("Case %s", tc.msg), func() {
+			suite.SetupTest() // reset
+			ctx := suite.chainA.GetContext()
+			k := suite.chainA.App.IBCKeeper.ConnectionKeeper
+
+			var clientID string
+			switch {
+			case tc.self:
+				// client of chain A on chain A
+				clientID = testClientIDA
+				suite.chainA.CreateClient(suite.chainA)
+			case tc.useLocalhost:
+				clientID = clientexported.ClientTypeLocalHost
+				suite.chainA.App.IBCKeeper.ClientKeeper.SetClientState(
+					ctx, localhosttypes.NewClientState(testClientIDA, suite.chainA.Header.Height),
+				)
+			default:
+				clientID = testClientIDB
+				suite.chainA.CreateClient(suite.chainB)
+			}
+
+			counterparty := connection.NewCounterparty(testClientIDA, testConnectionIDB, commitmenttypes.NewMerklePrefix(k.GetCommitmentPrefix().Bytes()))
+			ctx = suite.chainA.GetContext()
+
+			err := k.ConnOpenInit(ctx, testConnectionIDA, clientID, counterparty)
+			if tc.self {
+				suite.Require().Error(err, "invalid test case %d passed: %s", i, tc.msg)
+				suite.Require().True(types.ErrSelfConnection.Is(err), "unexpected error on test case %d: %s", i, err)
+			} else {
+				suite.Require().NoError(err, "valid test case %d failed: %s", i, tc.msg)
+			}
+
+			// the proofs are irrelevant, the connection is rejected before their
+			// verification
+			err = k.ConnOpenTry(
+				ctx, testConnectionID3, counterparty, clientID, connection.GetCompatibleVersions(),
+				commitmenttypes.MerkleProof{}, commitmenttypes.MerkleProof{}, 1, 1,
+			)
+			suite.Require().Error(err, "invalid test case %d passed: %s", i, tc.msg)
+			suite.Require().Equal(tc.self, types.ErrSelfConnection.Is(err), "unexpected error on test case %d: %s", i, err)
+		})
+	}
+}
+
 // TestConnOpenTry - Chain B (ID #2) calls ConnOpenTry to verify the state of
 // connection on Chain A (ID #1) is INIT
 func (suite *KeeperTestSuite) TestConnOpenTry() {
@@ -137,6 +438,70 @@ func (suite *KeeperTestSuite) TestConnOpenTry() {
 	}
 }
 
+// TestConnOpenTryConsensusStateAge - Chain B (ID #2) rejects a TRY whose proof
+// height lags its latest consensus state of Chain A (ID #1) by more than the
+// maximum age
+func (suite *KeeperTestSuite) TestConnOpenTryConsensusStateAge() {
+	testCases := []struct {
+		msg     string
+		maxAge  uint64
+		lag     int // client updates after the proofs were queried
+		expPass bool
+	}{
+		{"unbounded age", 0, 3, true},
+		{"fresh proof height", 3, 0, true},
+		{"proof height at the maximum age", 3, 3, true},
+		{"stale proof height", 2, 3, false},
+	}
+
+	for i, tc := range testCases {
+		tc := tc
+		i := i
+		suite.Run(fmt.Sprintf("Case %s", tc.msg), func() {
+			suite.SetupTest() // reset
+
+			suite.chainB.CreateClient(suite.chainA)
+			suite.chainA.CreateClient(suite.chainB)
+			suite.chainA.createConnection(testConnectionIDA, testConnectionIDB, testClientIDB, testClientIDA, types.INIT)
+			suite.chainB.updateClient(suite.chainA)
+			suite.chainA.updateClient(suite.chainB)
+			suite.chainB.updateClient(suite.chainA)
+			suite.chainA.updateClient(suite.chainB)
+			consensusHeight := suite.chainB.Header.GetHeight() - 1
+
+			proofInit, proofHeight := queryProof(suite.chainA, host.KeyConnection(testConnectionIDA))
+			proofConsensus, _ := queryProof(suite.chainA, prefixedClientKey(testClientIDB, host.KeyConsensusState(consensusHeight)))
+
+			// chainB keeps updating its client of chainA after the proofs were queried
+			for j := 0; j < tc.lag; j++ {
+				suite.chainB.updateClient(suite.chainA)
+			}
+
+			clientState, found := suite.chainB.App.IBCKeeper.ClientKeeper.GetClientState(suite.chainB.GetContext(), testClientIDA)
+			suite.Require().True(found)
+			suite.Require().Equal(proofHeight+1+uint64(tc.lag), clientState.GetLatestHeight())
+
+			suite.chainB.App.IBCKeeper.ConnectionKeeper.SetMaxConsensusStateAge(tc.maxAge)
+			counterparty := connection.NewCounterparty(
+				testClientIDB, testConnectionIDA, commitmenttypes.NewMerklePrefix(suite.chainB.App.IBCKeeper.ConnectionKeeper.GetCommitmentPrefix().Bytes()),
+			)
+
+			err := suite.chainB.App.IBCKeeper.ConnectionKeeper.ConnOpenTry(
+				suite.chainB.GetContext(), testConnectionIDB, counterparty, testClientIDA,
+				connection.GetCompatibleVersions(), proofInit, proofConsensus,
+				proofHeight+1, consensusHeight,
+			)
+
+			if tc.expPass {
+				suite.Require().NoError(err, "valid test case %d failed: %s", i, tc.msg)
+			} else {
+				suite.Require().Error(err, "invalid test case %d passed: %s", i, tc.msg)
+				suite.Require().True(types.ErrProofHeightTooOld.Is(err), "unexpected error on test case %d: %s", i, err)
+			}
+		})
+	}
+}
+
 // TestConnOpenAck - Chain A (ID #1) calls TestConnOpenAck to acknowledge (ACK state)
 // the initialization (TRYINIT) of the connection on  Chain B (ID #2).
 func (suite *KeeperTestSuite) TestConnOpenAck() {
@@ -236,6 +601,131 @@ func (suite *KeeperTestSuite) TestConnOpenAck() {
 	}
 }
 
+// TestConnOpenAckProofHeight - Chain A (ID #1) rejects an ACK whose proof height
+// is ahead of the latest consensus state it holds for Chain B (ID #2)
+func (suite *KeeperTestSuite) TestConnOpenAckProofHeight() {
+	version := connection.GetCompatibleVersions()[0]
+
+	testCases := []struct {
+		msg         string
+		heightDelta int64 // delta from the latest height of chainA's client for chainB
+		expPass     bool
+	}{
+		{"exactly latest height", 0, true},
+		{"future height", 1, false},
+		{"far future height", 100, false},
+	}
+
+	for i, tc := range testCases {
+		tc := tc
+		i := i
+		suite.Run(fmt.Sprintf("Case %s", tc.msg), func() {
+			suite.SetupTest() // reset
+
+			suite.chainA.CreateClient(suite.chainB)
+			suite.chainB.CreateClient(suite.chainA)
+			suite.chainB.createConnection(testConnectionIDB, testConnectionIDA, testClientIDA, testClientIDB, types.TRYOPEN)
+			suite.chainA.createConnection(testConnectionIDA, testConnectionIDB, testClientIDB, testClientIDA, types.INIT)
+			suite.chainB.updateClient(suite.chainA)
+			suite.chainA.updateClient(suite.chainB)
+			consensusHeight := suite.chainB.Header.GetHeight()
+
+			clientState, found := suite.chainA.App.IBCKeeper.ClientKeeper.GetClientState(suite.chainA.GetContext(), testClientIDB)
+			suite.Require().True(found)
+			latestHeight := clientState.GetLatestHeight()
+
+			connectionKey := host.KeyConnection(testConnectionIDB)
+			proofTry, proofHeight := queryProof(suite.chainB, connectionKey)
+			suite.Require().Equal(latestHeight, proofHeight+1)
+
+			consensusKey := prefixedClientKey(testClientIDA, host.KeyConsensusState(consensusHeight))
+			proofConsensus, _ := queryProof(suite.chainB, consensusKey)
+
+			err := suite.chainA.App.IBCKeeper.ConnectionKeeper.ConnOpenAck(
+				suite.chainA.GetContext(), testConnectionIDA, version, proofTry, proofConsensus,
+				uint64(int64(latestHeight)+tc.heightDelta), consensusHeight,
+			)
+
+			if tc.expPass {
+				suite.Require().NoError(err, "valid test case %d failed: %s", i, tc.msg)
+			} else {
+				suite.Require().Error(err, "invalid test case %d passed: %s", i, tc.msg)
+				suite.Require().True(types.ErrProofHeightTooHigh.Is(err), "invalid test case %d returned wrong error: %s", i, tc.msg)
+			}
+		})
+	}
+}
+
+// TestConnOpenCounterpartyConnectionID - Chain B (ID #2) records the connection
+// identifier chosen by Chain A (ID #1) on TRY, and Chain A rejects an ACK if
+// Chain B switched the counterparty connection identifier mid-handshake
+func (suite *KeeperTestSuite) TestConnOpenCounterpartyConnectionID() {
+	version := connection.GetCompatibleVersions()[0]
+
+	testCases := []struct {
+		msg                      string
+		counterpartyConnectionID string // counterparty connection id stored on chainB
+		expPass                  bool
+	}{
+		{"consistent counterparty connection id", testConnectionIDA, true},
+		{"switched counterparty connection id", "connectionc", false},
+	}
+
+	for i, tc := range testCases {
+		tc := tc
+		i := i
+		suite.Run(fmt.Sprintf("Case %s", tc.msg), func() {
+			suite.SetupTest() // reset
+
+			suite.chainB.CreateClient(suite.chainA)
+			suite.chainA.CreateClient(suite.chainB)
+			suite.chainA.createConnection(testConnectionIDA, testConnectionIDB, testClientIDB, testClientIDA, types.INIT)
+			suite.chainB.updateClient(suite.chainA)
+			suite.chainA.updateClient(suite.chainB)
+			suite.chainB.updateClient(suite.chainA)
+			suite.chainA.updateClient(suite.chainB)
+			consensusHeight := suite.chainB.Header.GetHeight() - 1
+
+			proofInit, proofHeight := queryProof(suite.chainA, host.KeyConnection(testConnectionIDA))
+			proofConsensus, _ := queryProof(suite.chainA, prefixedClientKey(testClientIDB, host.KeyConsensusState(consensusHeight)))
+
+			tryMsg := types.NewMsgConnectionOpenTry(
+				testConnectionIDB, testClientIDA, testConnectionIDA, testClientIDB,
+				commitmenttypes.NewMerklePrefix(suite.chainA.App.IBCKeeper.ConnectionKeeper.GetCommitmentPrefix().Bytes()),
+				connection.GetCompatibleVersions(), proofInit, proofConsensus,
+				proofHeight+1, consensusHeight, sdk.AccAddress("signer"),
+			)
+			_, err := connection.HandleMsgConnectionOpenTry(suite.chainB.GetContext(), suite.chainB.App.IBCKeeper.ConnectionKeeper, tryMsg)
+			suite.Require().NoError(err, "test case %d failed on TRY: %s", i, tc.msg)
+
+			stored, found := suite.chainB.App.IBCKeeper.ConnectionKeeper.GetConnection(suite.chainB.GetContext(), testConnectionIDB)
+			suite.Require().True(found)
+			suite.Require().Equal(testConnectionIDA, stored.Counterparty.ConnectionID, "test case %d didn't persist the counterparty connection id: %s", i, tc.msg)
+
+			// chainB switches the counterparty connection id after TRY
+			stored.Counterparty.ConnectionID = tc.counterpartyConnectionID
+			suite.chainB.App.IBCKeeper.ConnectionKeeper.SetConnection(suite.chainB.GetContext(), testConnectionIDB, stored)
+			suite.chainB.updateClient(suite.chainA)
+			suite.chainA.updateClient(suite.chainB)
+			consensusHeight = suite.chainB.Header.GetHeight()
+
+			proofTry, proofHeight := queryProof(suite.chainB, host.KeyConnection(testConnectionIDB))
+			proofConsensus, _ = queryProof(suite.chainB, prefixedClientKey(testClientIDA, host.KeyConsensusState(consensusHeight)))
+
+			ackMsg := types.NewMsgConnectionOpenAck(
+				testConnectionIDA, proofTry, proofConsensus, proofHeight+1, consensusHeight, version, sdk.AccAddress("signer"),
+			)
+			_, err = connection.HandleMsgConnectionOpenAck(suite.chainA.GetContext(), suite.chainA.App.IBCKeeper.ConnectionKeeper, ackMsg)
+
+			if tc.expPass {
+				suite.Require().NoError(err, "valid test case %d failed: %s", i, tc.msg)
+			} else {
+				suite.Require().Error(err, "invalid test case %d passed: %s", i, tc.msg)
+			}
+		})
+	}
+}
+
 // TestConnOpenConfirm - Chain B (ID #2) calls ConnOpenConfirm to confirm that
 // Chain A (ID #1) state is now OPEN.
 func (suite *KeeperTestSuite) TestConnOpenConfirm() {
@@ -294,3 +784,172 @@ type testCase = struct {
 	malleate func()
 	expPass  bool
 }
+
+// TestHandleMsgConnectionOpenConfirmResubmit - a relayer resubmits the
+// MsgConnectionOpenConfirm that already opened the connection on Chain B (ID #2)
+func (suite *KeeperTestSuite) TestHandleMsgConnectionOpenConfirmResubmit() {
+	suite.chainB.CreateClient(suite.chainA)
+	suite.chainA.CreateClient(suite.chainB)
+	suite.chainA.createConnection(testConnectionIDA, testConnectionIDB, testClientIDB, testClientIDA, types.OPEN)
+	suite.chainB.createConnection(testConnectionIDB, testConnectionIDA, testClientIDA, testClientIDB, types.TRYOPEN)
+	suite.chainB.updateClient(suite.chainA)
+
+	proofAck, proofHeight := queryProof(suite.chainA, host.KeyConnection(testConnectionIDA))
+	msg := types.NewMsgConnectionOpenConfirm(testConnectionIDB, proofAck, proofHeight+1, sdk.AccAddress("signer"))
+
+	countEvents := func(events sdk.Events) (count int) {
+		for _, event := range events {
+			if event.Type == types.EventTypeConnectionOpenConfirm {
+				count++
+			}
+		}
+		return count
+	}
+
+	ctx := suite.chainB.GetContext().WithEventManager(sdk.NewEventManager())
+
+	res, err := connection.HandleMsgConnectionOpenConfirm(ctx, suite.chainB.App.IBCKeeper.ConnectionKeeper, msg)
+	suite.Require().NoError(err)
+	suite.Require().NotNil(res)
+	suite.Require().Equal(1, countEvents(ctx.EventManager().Events()))
+
+	// the connection is already OPEN so the resubmitted message fails without
+	// emitting a second event
+	res, err = connection.HandleMsgConnectionOpenConfirm(ctx, suite.chainB.App.IBCKeeper.ConnectionKeeper, msg)
+	suite.Require().Error(err)
+	suite.Require().Nil(res)
+	suite.Require().Equal(1, countEvents(ctx.EventManager().Events()))
+}
+
+// TestHandleMsgConnectionOpenResultLog - the handshake handlers on Chain A (ID #1)
+// and Chain B (ID #2) log the connection that transitioned and its new state
+func (suite *KeeperTestSuite) TestHandleMsgConnectionOpenResultLog() {
+	initMsg := types.NewMsgConnectionOpenInit(
+		testConnectionIDA, testClientIDB, testConnectionIDB, testClientIDA,
+		commitmenttypes.NewMerklePrefix(suite.chainB.App.IBCKeeper.ConnectionKeeper.GetCommitmentPrefix().Bytes()), sdk.AccAddress("signer"),
+	)
+
+	suite.chainA.CreateClient(suite.chainB)
+	res, err := connection.HandleMsgConnectionOpenInit(suite.chainA.GetContext(), suite.chainA.App.IBCKeeper.ConnectionKeeper, initMsg)
+	suite.Require().NoError(err)
+
+	var log types.HandshakeLog
+	suite.Require().NoError(types.SubModuleCdc.UnmarshalJSON([]byte(res.Log), &log))
+	suite.Require().Equal(types.NewHandshakeLog(testConnectionIDA, types.INIT, 0), log)
+
+	suite.SetupTest() // reset
+
+	suite.chainB.CreateClient(suite.chainA)
+	suite.chainA.CreateClient(suite.chainB)
+	suite.chainA.createConnection(testConnectionIDA, testConnectionIDB, testClientIDB, testClientIDA, types.OPEN)
+	suite.chainB.createConnection(testConnectionIDB, testConnectionIDA, testClientIDA, testClientIDB, types.TRYOPEN)
+	suite.chainB.updateClient(suite.chainA)
+
+	proofAck, proofHeight := queryProof(suite.chainA, host.KeyConnection(testConnectionIDA))
+	confirmMsg := types.NewMsgConnectionOpenConfirm(testConnectionIDB, proofAck, proofHeight+1, sdk.AccAddress("signer"))
+
+	res, err = connection.HandleMsgConnectionOpenConfirm(suite.chainB.GetContext(), suite.chainB.App.IBCKeeper.ConnectionKeeper, confirmMsg)
+	suite.Require().NoError(err)
+
+	suite.Require().NoError(types.SubModuleCdc.UnmarshalJSON([]byte(res.Log), &log))
+	suite.Require().Equal(types.NewHandshakeLog(testConnectionIDB, types.OPEN, proofHeight+1), log)
+	suite.Require().Contains(res.Log, testConnectionIDB)
+	suite.Require().Contains(res.Log, types.OPEN.String())
+}
+
+// TestHandleMsgConnectionOpenEvents - the connection handlers emit the standard
+// set of connection attributes for every handshake step
+func (suite *KeeperTestSuite) TestHandleMsgConnectionOpenEvents() {
+	expKeys := []string{
+		types.AttributeKeyConnectionID, types.AttributeKeyClientID,
+		types.AttributeKeyCounterpartyClientID, types.AttributeKeyCounterpartyConnectionID,
+	}
+
+	requireEvent := func(res *sdk.Result, eventType, connectionID, clientID, counterpartyClientID, counterpartyConnectionID string) {
+		expValues := []string{connectionID, clientID, counterpartyClientID, counterpartyConnectionID}
+
+		found := false
+		for _, event := range res.Events {
+			switch event.Type {
+			case eventType:
+				found = true
+				suite.Require().Len(event.Attributes, len(expKeys), eventType)
+				for i, attr := range event.Attributes {
+					suite.Require().Equal(expKeys[i], string(attr.Key), eventType)
+					suite.Require().Equal(expValues[i], string(attr.Value), eventType)
+				}
+			case sdk.EventTypeMessage:
+				suite.Require().Equal(sdk.AttributeKeyModule, string(event.Attributes[0].Key))
+				suite.Require().Equal(types.AttributeValueCategory, string(event.Attributes[0].Value))
+			}
+		}
+		suite.Require().True(found, "no %s event", eventType)
+	}
+
+	// INIT
+	suite.chainA.CreateClient(suite.chainB)
+	initMsg := types.NewMsgConnectionOpenInit(
+		testConnectionIDA, testClientIDB, testConnectionIDB, testClientIDA,
+		commitmenttypes.NewMerklePrefix(suite.chainB.App.IBCKeeper.ConnectionKeeper.GetCommitmentPrefix().Bytes()), sdk.AccAddress("signer"),
+	)
+	res, err := connection.HandleMsgConnectionOpenInit(suite.chainA.GetContext(), suite.chainA.App.IBCKeeper.ConnectionKeeper, initMsg)
+	suite.Require().NoError(err)
+	requireEvent(res, types.EventTypeConnectionOpenInit, testConnectionIDA, testClientIDB, testClientIDA, testConnectionIDB)
+
+	// TRYOPEN
+	suite.SetupTest() // reset
+	suite.chainB.CreateClient(suite.chainA)
+	suite.chainA.CreateClient(suite.chainB)
+	suite.chainA.createConnection(testConnectionIDA, testConnectionIDB, testClientIDB, testClientIDA, types.INIT)
+	suite.chainB.updateClient(suite.chainA)
+	suite.chainA.updateClient(suite.chainB)
+	suite.chainB.updateClient(suite.chainA)
+	suite.chainA.updateClient(suite.chainB)
+	consensusHeight := suite.chainB.Header.GetHeight() - 1
+
+	proofInit, proofHeight := queryProof(suite.chainA, host.KeyConnection(testConnectionIDA))
+	proofConsensus, _ := queryProof(suite.chainA, prefixedClientKey(testClientIDB, host.KeyConsensusState(consensusHeight)))
+	tryMsg := types.NewMsgConnectionOpenTry(
+		testConnectionIDB, testClientIDA, testConnectionIDA, testClientIDB,
+		commitmenttypes.NewMerklePrefix(suite.chainA.App.IBCKeeper.ConnectionKeeper.GetCommitmentPrefix().Bytes()),
+		connection.GetCompatibleVersions(), proofInit, proofConsensus,
+		proofHeight+1, consensusHeight, sdk.AccAddress("signer"),
+	)
+	res, err = connection.HandleMsgConnectionOpenTry(suite.chainB.GetContext(), suite.chainB.App.IBCKeeper.ConnectionKeeper, tryMsg)
+	suite.Require().NoError(err)
+	requireEvent(res, types.EventTypeConnectionOpenTry, testConnectionIDB, testClientIDA, testClientIDB, testConnectionIDA)
+
+	// OPEN on ACK
+	suite.SetupTest() // reset
+	suite.chainA.CreateClient(suite.chainB)
+	suite.chainB.CreateClient(suite.chainA)
+	suite.chainB.createConnection(testConnectionIDB, testConnectionIDA, testClientIDA, testClientIDB, types.TRYOPEN)
+	suite.chainA.createConnection(testConnectionIDA, testConnectionIDB, testClientIDB, testClientIDA, types.INIT)
+	suite.chainB.updateClient(suite.chainA)
+	suite.chainA.updateClient(suite.chainB)
+	consensusHeight = suite.chainB.Header.GetHeight()
+
+	proofTry, proofHeight := queryProof(suite.chainB, host.KeyConnection(testConnectionIDB))
+	proofConsensus, _ = queryProof(suite.chainB, prefixedClientKey(testClientIDA, host.KeyConsensusState(consensusHeight)))
+	ackMsg := types.NewMsgConnectionOpenAck(
+		testConnectionIDA, proofTry, proofConsensus, proofHeight+1, consensusHeight,
+		connection.GetCompatibleVersions()[0], sdk.AccAddress("signer"),
+	)
+	res, err = connection.HandleMsgConnectionOpenAck(suite.chainA.GetContext(), suite.chainA.App.IBCKeeper.ConnectionKeeper, ackMsg)
+	suite.Require().NoError(err)
+	requireEvent(res, types.EventTypeConnectionOpenAck, testConnectionIDA, testClientIDB, testClientIDA, testConnectionIDB)
+
+	// OPEN on CONFIRM
+	suite.SetupTest() // reset
+	suite.chainB.CreateClient(suite.chainA)
+	suite.chainA.CreateClient(suite.chainB)
+	suite.chainA.createConnection(testConnectionIDA, testConnectionIDB, testClientIDB, testClientIDA, types.OPEN)
+	suite.chainB.createConnection(testConnectionIDB, testConnectionIDA, testClientIDA, testClientIDB, types.TRYOPEN)
+	suite.chainB.updateClient(suite.chainA)
+
+	proofAck, proofHeight := queryProof(suite.chainA, host.KeyConnection(testConnectionIDA))
+	confirmMsg := types.NewMsgConnectionOpenConfirm(testConnectionIDB, proofAck, proofHeight+1, sdk.AccAddress("signer"))
+	res, err = connection.HandleMsgConnectionOpenConfirm(suite.chainB.GetContext(), suite.chainB.App.IBCKeeper.ConnectionKeeper, confirmMsg)
+	suite.Require().NoError(err)
+	requireEvent(res, types.EventTypeConnectionOpenConfirm, testConnectionIDB, testClientIDA, testClientIDB, testConnectionIDA)
+}
@@ -1,6 +1,7 @@
 package keeper
 
 import (
+	"bytes"
 	"fmt"
 
 	"github.com/tendermint/tendermint/libs/log"
@@ -22,6 +23,10 @@ type Keeper struct {
 	aminoCdc     *codec.Codec    // amino codec. TODO: remove after clients have been migrated to proto
 	cdc          codec.Marshaler // hybrid codec
 	clientKeeper types.ClientKeeper
+
+	serializedHandshakes bool                        // allow a single connection handshake in progress per client
+	allowedClientTypes   []clientexported.ClientType // client types allowed on handshakes, all if empty
+	maxConsensusStateAge uint64                      // max lag of a TRY proof height behind the latest client height, unbounded if zero
 }
 
 // NewKeeper creates a new IBC connection Keeper instance
@@ -34,6 +39,28 @@ func NewKeeper(aminoCdc *codec.Codec, cdc codec.Marshaler, key sdk.StoreKey, ck
 	}
 }
 
+// SetSerializedHandshakes enables or disables serialized connection handshakes.
+// When enabled, a new connection can only be initialized for a client once all
+// of its other connections are OPEN.
+func (k *Keeper) SetSerializedHandshakes(serialized bool) {
+	k.serializedHandshakes = serialized
+}
+
+// SetAllowedClientTypes restricts the connection handshakes to the connections
+// whose underlying client is of one of the given types. All client types are
+// allowed if none is provided. The client types are copied, so later changes to
+// the given slice don't change the allowed types.
+func (k *Keeper) SetAllowedClientTypes(clientTypes ...clientexported.ClientType) {
+	k.allowedClientTypes = append([]clientexported.ClientType(nil), clientTypes...)
+}
+
+// SetMaxConsensusStateAge bounds the number of heights by which the proof height
+// of a connection TRY may lag the latest consensus state height of the client.
+// The lag is unbounded if the maximum age is zero.
+func (k *Keeper) SetMaxConsensusStateAge(maxAge uint64) {
+	k.maxConsensusStateAge = maxAge
+}
+
 // Logger returns a module-specific logger.
 func (k Keeper) Logger(ctx sdk.Context) log.Logger {
 	return ctx.Logger().With("module", fmt.Sprintf("x/%s/%s", host.ModuleName, types.SubModuleName))
@@ -66,6 +93,28 @@ func (k Keeper) SetConnection(ctx sdk.Context, connectionID string, connection t
 	store.Set(host.KeyConnection(connectionID), bz)
 }
 
+// GetConnectionMetadata returns the metadata of the handshake message that
+// created the given connection
+func (k Keeper) GetConnectionMetadata(ctx sdk.Context, connectionID string) (types.HandshakeMeta, error) {
+	store := ctx.KVStore(k.storeKey)
+	bz := store.Get(host.KeyConnectionMetadata(connectionID))
+	if bz == nil {
+		return types.HandshakeMeta{}, sdkerrors.Wrap(types.ErrConnectionMetadataNotFound, connectionID)
+	}
+
+	var meta types.HandshakeMeta
+	k.aminoCdc.MustUnmarshalBinaryBare(bz, &meta)
+	return meta, nil
+}
+
+// SetConnectionMetadata sets the metadata of the handshake message that created
+// the given connection
+func (k Keeper) SetConnectionMetadata(ctx sdk.Context, connectionID string, meta types.HandshakeMeta) {
+	store := ctx.KVStore(k.storeKey)
+	bz := k.aminoCdc.MustMarshalBinaryBare(meta)
+	store.Set(host.KeyConnectionMetadata(connectionID), bz)
+}
+
 // GetTimestampAtHeight returns the timestamp in nanoseconds of the consensus state at the
 // given height.
 func (k Keeper) GetTimestampAtHeight(ctx sdk.Context, connection types.ConnectionEnd, height uint64) (uint64, error) {
@@ -151,6 +200,108 @@ func (k Keeper) GetAllConnections(ctx sdk.Context) (connections []types.Connecti
 	return connections
 }
 
+// MigrateConnectionPrefixes rewrites the counterparty commitment prefix of all
+// the stored connections using the provided callback, and returns the number of
+// connections whose prefix changed. The migrated connections are validated before
+// any of them is stored, so no connection is modified if an error is returned.
+func (k Keeper) MigrateConnectionPrefixes(
+	ctx sdk.Context, rewrite func(old commitmenttypes.MerklePrefix) commitmenttypes.MerklePrefix,
+) (int, error) {
+	var migrated []types.ConnectionEnd
+	k.IterateConnections(ctx, func(connection types.ConnectionEnd) bool {
+		prefix := rewrite(connection.Counterparty.Prefix)
+		if bytes.Equal(prefix.Bytes(), connection.Counterparty.Prefix.Bytes()) {
+			return false
+		}
+
+		connection.Counterparty.Prefix = prefix
+		migrated = append(migrated, connection)
+		return false
+	})
+
+	for _, connection := range migrated {
+		if err := connection.ValidateBasic(); err != nil {
+			return 0, sdkerrors.Wrapf(err, "cannot migrate prefix of connection %s", connection.ID)
+		}
+	}
+
+	for _, connection := range migrated {
+		k.SetConnection(ctx, connection.ID, connection)
+	}
+
+	if len(migrated) > 0 {
+		k.Logger(ctx).Info(fmt.Sprintf("migrated the counterparty prefix of %d connections", len(migrated)))
+	}
+	return len(migrated), nil
+}
+
+// ExportConnections returns a snapshot of all the stored connection ends, along
+// with the metadata of the handshake message that created each of them.
+func (k Keeper) ExportConnections(ctx sdk.Context) ([]types.ConnectionSnapshot, error) {
+	snapshots := []types.ConnectionSnapshot{}
+	for _, connection := range k.GetAllConnections(ctx) {
+		if err := connection.ValidateBasic(); err != nil {
+			return nil, sdkerrors.Wrapf(err, "cannot export connection %s", connection.ID)
+		}
+
+		var metadata *types.HandshakeMeta
+		if meta, err := k.GetConnectionMetadata(ctx, connection.ID); err == nil {
+			metadata = &meta
+		}
+		snapshots = append(snapshots, types.NewConnectionSnapshot(connection, metadata))
+	}
+
+	return snapshots, nil
+}
+
+// ImportConnections restores the connection ends of the given snapshots, as
+// exported by ExportConnections, along with their metadata, and associates each
+// connection to its client. The snapshots are validated before
+// any of them is stored, so no connection is restored if an error is returned.
+func (k Keeper) ImportConnections(ctx sdk.Context, snapshots []types.ConnectionSnapshot) error {
+	seen := make(map[string]bool, len(snapshots))
+	for _, snapshot := range snapshots {
+		connectionID := snapshot.Connection.ID
+		if err := snapshot.ValidateBasic(); err != nil {
+			return sdkerrors.Wrapf(err, "cannot import connection %s", connectionID)
+		}
+
+		if seen[connectionID] {
+			return sdkerrors.Wrapf(types.ErrConnectionExists, "duplicate connection %s in snapshot", connectionID)
+		}
+		seen[connectionID] = true
+
+		if _, found := k.GetConnection(ctx, connectionID); found {
+			return sdkerrors.Wrapf(types.ErrConnectionExists, "cannot import connection %s", connectionID)
+		}
+	}
+
+	for _, snapshot := range snapshots {
+		connection := snapshot.Connection
+		k.SetConnection(ctx, connection.ID, connection)
+		if snapshot.Metadata != nil {
+			k.SetConnectionMetadata(ctx, connection.ID, *snapshot.Metadata)
+		}
+
+		paths, _ := k.GetClientConnectionPaths(ctx, connection.ClientID)
+		associated := false
+		for _, path := range paths {
+			if path == connection.ID {
+				associated = true
+				break
+			}
+		}
+		if !associated {
+			k.SetClientConnectionPaths(ctx, connection.ClientID, append(paths, connection.ID))
+		}
+	}
+
+	if len(snapshots) > 0 {
+		k.Logger(ctx).Info(fmt.Sprintf("imported %d connections", len(snapshots)))
+	}
+	return nil
+}
+
 // addConnectionToClient is used to add a connection identifier to the set of
 // connections associated with a client.
 func (k Keeper) addConnectionToClient(ctx sdk.Context, clientID, connectionID string) error {
@@ -169,6 +320,91 @@ func (k Keeper) addConnectionToClient(ctx sdk.Context, clientID, connectionID st
 	return nil
 }
 
+// hasHandshakeInProgress returns true if the client has a connection that is
+// not yet OPEN.
+func (k Keeper) hasHandshakeInProgress(ctx sdk.Context, clientID string) bool {
+	conns, found := k.GetClientConnectionPaths(ctx, clientID)
+	if !found {
+		return false
+	}
+
+	for _, connectionID := range conns {
+		connection, found := k.GetConnection(ctx, connectionID)
+		if found && connection.State != types.OPEN {
+			return true
+		}
+	}
+
+	return false
+}
+
+// checkClientTypeAllowed returns an error if the type of the given client is not
+// allowed on connection handshakes.
+func (k Keeper) checkClientTypeAllowed(ctx sdk.Context, clientID string) error {
+	if len(k.allowedClientTypes) == 0 {
+		return nil
+	}
+
+	clientState, found := k.clientKeeper.GetClientState(ctx, clientID)
+	if !found {
+		return sdkerrors.Wrap(clienttypes.ErrClientNotFound, clientID)
+	}
+
+	for _, clientType := range k.allowedClientTypes {
+		if clientState.ClientType() == clientType {
+			return nil
+		}
+	}
+
+	return sdkerrors.Wrapf(
+		types.ErrClientTypeNotAllowed,
+		"client %s has type %s", clientID, clientState.ClientType(),
+	)
+}
+
+// checkSelfConnection returns an error if the given client tracks the local
+// chain, which would make the connection a loop back to itself. The localhost
+// client is exempted since it is meant to connect the chain to itself.
+func (k Keeper) checkSelfConnection(ctx sdk.Context, clientID string) error {
+	clientState, found := k.clientKeeper.GetClientState(ctx, clientID)
+	if !found || clientState.ClientType() == clientexported.Localhost {
+		return nil
+	}
+
+	if clientState.GetChainID() == ctx.ChainID() {
+		return sdkerrors.Wrapf(
+			types.ErrSelfConnection,
+			"client %s tracks the local chain %s", clientID, ctx.ChainID(),
+		)
+	}
+
+	return nil
+}
+
+// checkConsensusStateAge returns an error if the proof height lags the latest
+// consensus state height of the given client by more than the maximum age.
+func (k Keeper) checkConsensusStateAge(ctx sdk.Context, clientID string, proofHeight uint64) error {
+	if k.maxConsensusStateAge == 0 {
+		return nil
+	}
+
+	clientState, found := k.clientKeeper.GetClientState(ctx, clientID)
+	if !found {
+		return sdkerrors.Wrap(clienttypes.ErrClientNotFound, clientID)
+	}
+
+	latestHeight := clientState.GetLatestHeight()
+	if latestHeight > proofHeight && latestHeight-proofHeight > k.maxConsensusStateAge {
+		return sdkerrors.Wrapf(
+			types.ErrProofHeightTooOld,
+			"proof height %d lags latest consensus state height %d for client %s by more than %d",
+			proofHeight, latestHeight, clientID, k.maxConsensusStateAge,
+		)
+	}
+
+	return nil
+}
+
 // removeConnectionFromClient is used to remove a connection identifier from the
 // set of connections associated with a client.
 //
@@ -1,6 +1,7 @@
 package keeper_test
 
 import (
+	"bytes"
 	"fmt"
 	"testing"
 	"time"
@@ -97,6 +98,135 @@ func (suite *KeeperTestSuite) TestSetAndGetConnection() {
 	suite.Require().EqualValues(expConn, conn)
 }
 
+func (suite *KeeperTestSuite) TestMigrateConnectionPrefixes() {
+	oldPrefix := commitmenttypes.NewMerklePrefix([]byte("ibc"))
+	newPrefix := commitmenttypes.NewMerklePrefix([]byte("ibcv2"))
+	otherPrefix := commitmenttypes.NewMerklePrefix([]byte("other"))
+
+	toNewPrefix := func(old commitmenttypes.MerklePrefix) commitmenttypes.MerklePrefix {
+		if bytes.Equal(old.Bytes(), oldPrefix.Bytes()) {
+			return newPrefix
+		}
+		return old
+	}
+
+	testCases := []struct {
+		msg         string
+		rewrite     func(old commitmenttypes.MerklePrefix) commitmenttypes.MerklePrefix
+		expMigrated int
+		expPrefixes []commitmenttypes.MerklePrefix
+		expPass     bool
+	}{
+		{"migrate matching prefixes", toNewPrefix, 2, []commitmenttypes.MerklePrefix{newPrefix, newPrefix, otherPrefix}, true},
+		{"nothing to migrate", func(old commitmenttypes.MerklePrefix) commitmenttypes.MerklePrefix {
+			return old
+		}, 0, []commitmenttypes.MerklePrefix{oldPrefix, oldPrefix, otherPrefix}, true},
+		{"empty prefix fails without migrating", func(old commitmenttypes.MerklePrefix) commitmenttypes.MerklePrefix {
+			if bytes.Equal(old.Bytes(), otherPrefix.Bytes()) {
+				return commitmenttypes.MerklePrefix{}
+			}
+			return toNewPrefix(old)
+		}, 0, []commitmenttypes.MerklePrefix{oldPrefix, oldPrefix, otherPrefix}, false},
+	}
+
+	connectionIDs := []string{testConnectionIDA, testConnectionIDB, testConnectionID3}
+
+	for i, tc := range testCases {
+		tc := tc
+		suite.Run(fmt.Sprintf("Case %s", tc.msg), func() {
+			suite.SetupTest() // reset
+			ctx := suite.chainA.GetContext()
+			k := suite.chainA.App.IBCKeeper.ConnectionKeeper
+
+			for j, prefix := range []commitmenttypes.MerklePrefix{oldPrefix, oldPrefix, otherPrefix} {
+				counterparty := types.NewCounterparty(testClientIDB, testConnectionIDB, prefix)
+				k.SetConnection(ctx, connectionIDs[j], types.NewConnectionEnd(types.OPEN, connectionIDs[j], testClientIDA, counterparty, types.GetCompatibleVersions()))
+			}
+
+			migrated, err := k.MigrateConnectionPrefixes(ctx, tc.rewrite)
+
+			if tc.expPass {
+				suite.Require().NoError(err, "valid test case %d failed: %s", i, tc.msg)
+			} else {
+				suite.Require().Error(err, "invalid test case %d passed: %s", i, tc.msg)
+			}
+			suite.Require().Equal(tc.expMigrated, migrated)
+
+			for j, connectionID := range connectionIDs {
+				connection, found := k.GetConnection(ctx, connectionID)
+				suite.Require().True(found)
+				suite.Require().Equal(tc.expPrefixes[j], connection.Counterparty.Prefix)
+			}
+		})
+	}
+}
+
+func (suite *KeeperTestSuite) TestExportImportConnections() {
+	prefix := commitmenttypes.NewMerklePrefix([]byte("ibc"))
+	otherPrefix := commitmenttypes.NewMerklePrefix([]byte("other"))
+
+	expConnections := []types.ConnectionEnd{
+		types.NewConnectionEnd(types.INIT, testConnectionIDA, testClientIDA, types.NewCounterparty(testClientIDB, testConnectionIDB, prefix), types.GetCompatibleVersions()),
+		types.NewConnectionEnd(types.TRYOPEN, testConnectionIDB, testClientIDB, types.NewCounterparty(testClientIDA, testConnectionIDA, otherPrefix), []string{"1.0.0"}),
+		types.NewConnectionEnd(types.OPEN, testConnectionID3, testClientIDA, types.NewCounterparty(testClientID3, testConnectionIDB, prefix), types.GetCompatibleVersions()),
+	}
+
+	ctxA := suite.chainA.GetContext()
+	kA := suite.chainA.App.IBCKeeper.ConnectionKeeper
+	for _, connection := range expConnections {
+		kA.SetConnection(ctxA, connection.ID, connection)
+	}
+	expMeta := types.NewHandshakeMeta(sdk.AccAddress("signer"), 10, time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC))
+	kA.SetConnectionMetadata(ctxA, testConnectionIDB, expMeta)
+
+	snapshots, err := kA.ExportConnections(ctxA)
+	suite.Require().NoError(err)
+	suite.Require().Len(snapshots, len(expConnections))
+
+	// the snapshots are restored on another chain with state, versions,
+	// prefixes and metadata preserved
+	ctxB := suite.chainB.GetContext()
+	kB := suite.chainB.App.IBCKeeper.ConnectionKeeper
+	suite.Require().NoError(kB.ImportConnections(ctxB, snapshots))
+
+	suite.Require().Equal(expConnections, kB.GetAllConnections(ctxB))
+	meta, err := kB.GetConnectionMetadata(ctxB, testConnectionIDB)
+	suite.Require().NoError(err)
+	suite.Require().Equal(expMeta, meta)
+	_, err = kB.GetConnectionMetadata(ctxB, testConnectionIDA)
+	suite.Require().Error(err)
+
+	paths, found := kB.GetClientConnectionPaths(ctxB, testClientIDA)
+	suite.Require().True(found)
+	suite.Require().Equal([]string{testConnectionIDA, testConnectionID3}, paths)
+	paths, found = kB.GetClientConnectionPaths(ctxB, testClientIDB)
+	suite.Require().True(found)
+	suite.Require().Equal([]string{testConnectionIDB}, paths)
+
+	reexported, err := kB.ExportConnections(ctxB)
+	suite.Require().NoError(err)
+	suite.Require().Equal(snapshots, reexported)
+
+	// existing connections are not overwritten
+	suite.Require().Error(kB.ImportConnections(ctxB, snapshots[:1]))
+
+	// invalid or duplicate snapshots fail without restoring any connection
+	suite.SetupTest() // reset
+	ctxB = suite.chainB.GetContext()
+	kB = suite.chainB.App.IBCKeeper.ConnectionKeeper
+
+	invalid := types.NewConnectionSnapshot(types.NewConnectionEnd(types.OPEN, "(invalid)", testClientIDA, types.NewCounterparty(testClientIDB, testConnectionIDB, prefix), types.GetCompatibleVersions()), nil)
+	suite.Require().Error(kB.ImportConnections(ctxB, append(snapshots[:2:2], invalid)))
+	suite.Require().Error(kB.ImportConnections(ctxB, append(snapshots[:2:2], snapshots[0])))
+	suite.Require().Empty(kB.GetAllConnections(ctxB))
+
+	// an empty store exports an empty snapshot
+	snapshots, err = kB.ExportConnections(ctxB)
+	suite.Require().NoError(err)
+	suite.Require().Empty(snapshots)
+	suite.Require().NoError(kB.ImportConnections(ctxB, snapshots))
+}
+
 func (suite *KeeperTestSuite) TestSetAndGetClientConnectionPaths() {
 	_, existed := suite.chainA.App.IBCKeeper.ConnectionKeeper.GetClientConnectionPaths(suite.chainA.GetContext(), testClientIDA)
 	suite.False(existed)
@@ -43,6 +43,22 @@ func (c ConnectionEnd) GetCounterparty() exported.CounterpartyI {
 	return c.Counterparty
 }
 
+// GetCounterpartyClientID returns the client identifier of the counterparty
+func (c ConnectionEnd) GetCounterpartyClientID() string {
+	return c.Counterparty.GetClientID()
+}
+
+// GetCounterpartyConnectionID returns the connection identifier of the
+// counterparty
+func (c ConnectionEnd) GetCounterpartyConnectionID() string {
+	return c.Counterparty.GetConnectionID()
+}
+
+// GetCounterpartyPrefix returns the commitment prefix of the counterparty
+func (c ConnectionEnd) GetCounterpartyPrefix() commitmentexported.Prefix {
+	return c.Counterparty.GetPrefix()
+}
+
 // GetVersions implements the Connection interface
 func (c ConnectionEnd) GetVersions() []string {
 	return c.Versions
@@ -58,6 +74,9 @@ func (c ConnectionEnd) ValidateBasic() error {
 	if err := host.ClientIdentifierValidator(c.ClientID); err != nil {
 		return sdkerrors.Wrapf(err, "invalid client ID: %s", c.ClientID)
 	}
+	if _, ok := State_name[int32(c.State)]; !ok {
+		return sdkerrors.Wrapf(ErrInvalidConnectionState, "unknown connection state %d", c.State)
+	}
 	if len(c.Versions) == 0 {
 		return sdkerrors.Wrap(sdkerrors.ErrInvalidVersion, "missing connection versions")
 	}
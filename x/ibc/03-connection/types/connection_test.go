@@ -36,6 +36,16 @@ func TestConnectionValidateBasic(t *testing.T) {
 			ConnectionEnd{connectionID, "(clientID1)", []string{"1.0.0"}, INIT, Counterparty{clientID2, connectionID2, commitmenttypes.NewMerklePrefix([]byte("prefix"))}},
 			false,
 		},
+		{
+			"empty client id",
+			ConnectionEnd{connectionID, "", []string{"1.0.0"}, INIT, Counterparty{clientID2, connectionID2, commitmenttypes.NewMerklePrefix([]byte("prefix"))}},
+			false,
+		},
+		{
+			"unknown state",
+			ConnectionEnd{connectionID, clientID, []string{"1.0.0"}, State(4), Counterparty{clientID2, connectionID2, commitmenttypes.NewMerklePrefix([]byte("prefix"))}},
+			false,
+		},
 		{
 			"empty versions",
 			ConnectionEnd{connectionID, clientID, nil, INIT, Counterparty{clientID2, connectionID2, commitmenttypes.NewMerklePrefix([]byte("prefix"))}},
@@ -88,3 +98,17 @@ func TestCounterpartyValidateBasic(t *testing.T) {
 		}
 	}
 }
+
+func TestConnectionCounterpartyGetters(t *testing.T) {
+	prefix := commitmenttypes.NewMerklePrefix([]byte("prefix"))
+	connection := NewConnectionEnd(TRYOPEN, connectionID, clientID, NewCounterparty(clientID2, connectionID2, prefix), []string{"1.0.0"})
+
+	require.Equal(t, clientID2, connection.GetCounterpartyClientID())
+	require.Equal(t, connectionID2, connection.GetCounterpartyConnectionID())
+	require.Equal(t, prefix.Bytes(), connection.GetCounterpartyPrefix().Bytes())
+
+	empty := ConnectionEnd{}
+	require.Empty(t, empty.GetCounterpartyClientID())
+	require.Empty(t, empty.GetCounterpartyConnectionID())
+	require.True(t, empty.GetCounterpartyPrefix().IsEmpty())
+}
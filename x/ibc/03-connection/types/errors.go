@@ -13,4 +13,10 @@ var (
 	ErrInvalidConnectionState        = sdkerrors.Register(SubModuleName, 6, "invalid connection state")
 	ErrInvalidCounterparty           = sdkerrors.Register(SubModuleName, 7, "invalid counterparty connection")
 	ErrInvalidConnection             = sdkerrors.Register(SubModuleName, 8, "invalid connection")
+	ErrHandshakeInProgress           = sdkerrors.Register(SubModuleName, 9, "connection handshake already in progress")
+	ErrProofHeightTooHigh            = sdkerrors.Register(SubModuleName, 10, "proof height is greater than the latest consensus state height")
+	ErrClientTypeNotAllowed          = sdkerrors.Register(SubModuleName, 11, "client type not allowed")
+	ErrProofHeightTooOld             = sdkerrors.Register(SubModuleName, 12, "proof height lags the latest consensus state height by more than the maximum age")
+	ErrSelfConnection                = sdkerrors.Register(SubModuleName, 13, "connection counterparty is the local chain")
+	ErrConnectionMetadataNotFound    = sdkerrors.Register(SubModuleName, 14, "connection metadata not found")
 )
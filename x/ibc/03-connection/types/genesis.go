@@ -20,6 +20,27 @@ func NewConnectionPaths(id string, paths []string) ConnectionPaths {
 	}
 }
 
+// ConnectionSnapshot defines a connection end, including its handshake state, as
+// exported for state sync and relayer bootstrap, together with the metadata of
+// the handshake message that created it, if recorded.
+type ConnectionSnapshot struct {
+	Connection ConnectionEnd  `json:"connection" yaml:"connection"`
+	Metadata   *HandshakeMeta `json:"metadata,omitempty" yaml:"metadata,omitempty"`
+}
+
+// NewConnectionSnapshot creates a ConnectionSnapshot instance.
+func NewConnectionSnapshot(connection ConnectionEnd, metadata *HandshakeMeta) ConnectionSnapshot {
+	return ConnectionSnapshot{
+		Connection: connection,
+		Metadata:   metadata,
+	}
+}
+
+// ValidateBasic performs a basic validation of the snapshot's connection end.
+func (cs ConnectionSnapshot) ValidateBasic() error {
+	return cs.Connection.ValidateBasic()
+}
+
 // GenesisState defines the ibc connection submodule's genesis state.
 type GenesisState struct {
 	Connections           []ConnectionEnd   `json:"connections" yaml:"connections"`
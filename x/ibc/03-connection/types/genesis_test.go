@@ -81,3 +81,13 @@ func TestValidateGenesis(t *testing.T) {
 		}
 	}
 }
+
+func TestConnectionSnapshotValidateBasic(t *testing.T) {
+	counterparty := Counterparty{clientID2, connectionID2, commitmenttypes.NewMerklePrefix([]byte("prefix"))}
+
+	snapshot := NewConnectionSnapshot(NewConnectionEnd(TRYOPEN, connectionID, clientID, counterparty, []string{"1.0.0"}), nil)
+	require.NoError(t, snapshot.ValidateBasic())
+
+	snapshot = NewConnectionSnapshot(NewConnectionEnd(TRYOPEN, "(connectionIDONE)", clientID, counterparty, []string{"1.0.0"}), nil)
+	require.Error(t, snapshot.ValidateBasic())
+}
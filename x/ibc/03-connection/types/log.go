@@ -0,0 +1,24 @@
+package types
+
+// HandshakeLog defines the structured log returned on the result of a
+// successful connection handshake message.
+type HandshakeLog struct {
+	ConnectionID string `json:"connection_id" yaml:"connection_id"`
+	State        string `json:"state" yaml:"state"`
+	ProofHeight  uint64 `json:"proof_height,omitempty" yaml:"proof_height,omitempty"`
+}
+
+// NewHandshakeLog creates a new HandshakeLog instance. A zero proof height is
+// omitted from the log.
+func NewHandshakeLog(connectionID string, state State, proofHeight uint64) HandshakeLog {
+	return HandshakeLog{
+		ConnectionID: connectionID,
+		State:        state.String(),
+		ProofHeight:  proofHeight,
+	}
+}
+
+// String returns the JSON encoding of the log.
+func (hl HandshakeLog) String() string {
+	return string(SubModuleCdc.MustMarshalJSON(hl))
+}
@@ -0,0 +1,25 @@
+package types
+
+import (
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// HandshakeMeta defines the metadata of the handshake message that created a
+// connection end on this chain (ie OpenInit or OpenTry), recorded for auditing
+// which account opened each connection.
+type HandshakeMeta struct {
+	Signer sdk.AccAddress `json:"signer" yaml:"signer"`
+	Height int64          `json:"height" yaml:"height"`
+	Time   time.Time      `json:"time" yaml:"time"`
+}
+
+// NewHandshakeMeta creates a new HandshakeMeta instance.
+func NewHandshakeMeta(signer sdk.AccAddress, height int64, time time.Time) HandshakeMeta {
+	return HandshakeMeta{
+		Signer: signer,
+		Height: height,
+		Time:   time,
+	}
+}
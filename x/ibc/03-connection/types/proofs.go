@@ -0,0 +1,27 @@
+package types
+
+// Proof kinds required by the connection handshake messages
+const (
+	ProofKindConnection = "connection"
+	ProofKindConsensus  = "consensus"
+)
+
+// RequiredProofs returns the kinds of proofs a relayer must query from a
+// connection end in the given state in order to submit the next handshake
+// message to the counterparty chain:
+//
+// - INIT: MsgConnectionOpenTry requires the connection and consensus proofs
+// - TRYOPEN: MsgConnectionOpenAck requires the connection and consensus proofs
+// - OPEN: MsgConnectionOpenConfirm requires the connection proof
+//
+// No proofs are required for any other state.
+func RequiredProofs(state State) []string {
+	switch state {
+	case INIT, TRYOPEN:
+		return []string{ProofKindConnection, ProofKindConsensus}
+	case OPEN:
+		return []string{ProofKindConnection}
+	default:
+		return []string{}
+	}
+}
@@ -0,0 +1,30 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequiredProofs(t *testing.T) {
+	testCases := []struct {
+		name     string
+		state    State
+		expProof []string
+	}{
+		{"uninitialized", UNINITIALIZED, []string{}},
+		{"init", INIT, []string{ProofKindConnection, ProofKindConsensus}},
+		{"tryopen", TRYOPEN, []string{ProofKindConnection, ProofKindConsensus}},
+		{"open", OPEN, []string{ProofKindConnection}},
+		{"unknown state", State(100), []string{}},
+	}
+
+	for _, tc := range testCases {
+		require.Equal(t, tc.expProof, RequiredProofs(tc.state), tc.name)
+	}
+
+	// the returned slices are not shared between calls
+	proofs := RequiredProofs(INIT)
+	proofs[0] = "modified"
+	require.Equal(t, ProofKindConnection, RequiredProofs(INIT)[0])
+}
@@ -0,0 +1,52 @@
+package testutil
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cosmos/cosmos-sdk/x/ibc/23-commitment/exported"
+	"github.com/cosmos/cosmos-sdk/x/ibc/23-commitment/types"
+)
+
+// RequireMembership fails the test if the proof doesn't verify the membership of
+// the value at the given path against the root.
+func RequireMembership(t testing.TB, proof types.MerkleProof, root exported.Root, path exported.Path, value []byte) {
+	t.Helper()
+
+	err := proof.VerifyMembership(root, path, value)
+	require.NoError(t, err, "membership verification failed\n%s\nvalue: %X", describe(proof, root, path), value)
+}
+
+// RequireNonMembership fails the test if the proof doesn't verify the absence of
+// the given path against the root.
+func RequireNonMembership(t testing.TB, proof types.MerkleProof, root exported.Root, path exported.Path) {
+	t.Helper()
+
+	err := proof.VerifyNonMembership(root, path)
+	require.NoError(t, err, "non-membership verification failed\n%s", describe(proof, root, path))
+}
+
+// describe returns a human readable description of the verification arguments.
+func describe(proof types.MerkleProof, root exported.Root, path exported.Path) string {
+	var ops []string
+	if proof.Proof != nil {
+		for _, op := range proof.Proof.Ops {
+			ops = append(ops, fmt.Sprintf("%s(%X)", op.Type, op.Key))
+		}
+	}
+
+	var rootHash []byte
+	if root != nil {
+		rootHash = root.GetHash()
+	}
+
+	var pathStr string
+	if path != nil {
+		pathStr = path.String()
+	}
+
+	return fmt.Sprintf("root: %X\npath: %s\nproof ops: [%s]", rootHash, pathStr, strings.Join(ops, ", "))
+}
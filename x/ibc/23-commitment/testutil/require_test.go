@@ -0,0 +1,124 @@
+package testutil_test
+
+import (
+	"fmt"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	dbm "github.com/tendermint/tm-db"
+
+	"github.com/cosmos/cosmos-sdk/store/rootmulti"
+	storetypes "github.com/cosmos/cosmos-sdk/store/types"
+	"github.com/cosmos/cosmos-sdk/x/ibc/23-commitment/exported"
+	commitmenttestutil "github.com/cosmos/cosmos-sdk/x/ibc/23-commitment/testutil"
+	"github.com/cosmos/cosmos-sdk/x/ibc/23-commitment/types"
+)
+
+// mockTB records the failures of a test without failing the running test
+type mockTB struct {
+	testing.TB
+
+	failed bool
+	msg    string
+}
+
+func (m *mockTB) Helper() {}
+
+func (m *mockTB) Name() string { return "mockTB" }
+
+func (m *mockTB) Errorf(format string, args ...interface{}) {
+	m.failed = true
+	m.msg = fmt.Sprintf(format, args...)
+}
+
+func (m *mockTB) FailNow() {
+	m.failed = true
+	runtime.Goexit()
+}
+
+// run calls the assertion with a mock test, returning it once the assertion
+// returned or failed the test
+func run(assertion func(t testing.TB)) *mockTB {
+	m := &mockTB{}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		assertion(m)
+	}()
+	<-done
+	return m
+}
+
+func TestRequireMembership(t *testing.T) {
+	store := rootmulti.NewStore(dbm.NewMemDB())
+	storeKey := storetypes.NewKVStoreKey("iavlStoreKey")
+	store.MountStoreWithDB(storeKey, storetypes.StoreTypeIAVL, nil)
+	require.NoError(t, store.LoadVersion(0))
+
+	store.GetCommitKVStore(storeKey).Set([]byte("MYKEY"), []byte("MYVALUE"))
+	cid := store.Commit()
+	root := types.NewMerkleRoot(cid.Hash)
+
+	query := func(key string) types.MerkleProof {
+		res := store.Query(abci.RequestQuery{
+			Path:   fmt.Sprintf("/%s/key", storeKey.Name()), // required path to get key/value+proof
+			Data:   []byte(key),
+			Height: cid.Version,
+			Prove:  true,
+		})
+		require.NotNil(t, res.Proof)
+		return types.MerkleProof{Proof: res.Proof}
+	}
+
+	existence := query("MYKEY")
+	absence := query("MYABSENTKEY")
+	path := types.NewMerklePath([]string{storeKey.Name(), "MYKEY"})
+	absentPath := types.NewMerklePath([]string{storeKey.Name(), "MYABSENTKEY"})
+
+	testCases := []struct {
+		name      string
+		assertion func(t testing.TB)
+		expPass   bool
+	}{
+		{"membership", func(t testing.TB) {
+			commitmenttestutil.RequireMembership(t, existence, &root, path, []byte("MYVALUE"))
+		}, true},
+		{"membership of wrong value", func(t testing.TB) {
+			commitmenttestutil.RequireMembership(t, existence, &root, path, []byte("WRONGVALUE"))
+		}, false},
+		{"membership with absence proof", func(t testing.TB) {
+			commitmenttestutil.RequireMembership(t, absence, &root, absentPath, []byte("MYVALUE"))
+		}, false},
+		{"membership with empty proof", func(t testing.TB) {
+			commitmenttestutil.RequireMembership(t, types.MerkleProof{}, &root, path, []byte("MYVALUE"))
+		}, false},
+		{"non-membership", func(t testing.TB) {
+			commitmenttestutil.RequireNonMembership(t, absence, &root, absentPath)
+		}, true},
+		{"non-membership of existing key", func(t testing.TB) {
+			commitmenttestutil.RequireNonMembership(t, existence, &root, path)
+		}, false},
+		{"non-membership with wrong root", func(t testing.TB) {
+			wrongRoot := types.NewMerkleRoot([]byte("wrongroot"))
+			commitmenttestutil.RequireNonMembership(t, absence, &wrongRoot, absentPath)
+		}, false},
+		{"non-membership with nil root", func(t testing.TB) {
+			var nilRoot exported.Root
+			commitmenttestutil.RequireNonMembership(t, absence, nilRoot, absentPath)
+		}, false},
+	}
+
+	for _, tc := range testCases {
+		m := run(tc.assertion)
+		if tc.expPass {
+			require.False(t, m.failed, "%s: unexpected failure: %s", tc.name, m.msg)
+		} else {
+			require.True(t, m.failed, tc.name)
+			require.Contains(t, m.msg, "verification failed", tc.name)
+			require.Contains(t, m.msg, "root: ", tc.name)
+			require.Contains(t, m.msg, "path: ", tc.name)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package types
+
+import (
+	"github.com/tendermint/tendermint/crypto/merkle"
+
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/cosmos/cosmos-sdk/x/ibc/23-commitment/exported"
+)
+
+// AggregateRoots defines an ordered list of child commitment roots (eg: the roots
+// of several counterparty chains) that are committed to by a single aggregate
+// root.
+type AggregateRoots []MerkleRoot
+
+// NewAggregateRoot computes the aggregate root of the given child roots as the
+// simple merkle root of their hashes, in the given order.
+func NewAggregateRoot(roots []MerkleRoot) MerkleRoot {
+	return NewMerkleRoot(merkle.SimpleHashFromByteSlices(AggregateRoots(roots).hashes()))
+}
+
+// ProveChildRoot returns the membership witness (ie the simple merkle proof aunts)
+// of the child root at the given index.
+func (ar AggregateRoots) ProveChildRoot(index int) ([][]byte, error) {
+	if index < 0 || index >= len(ar) {
+		return nil, sdkerrors.Wrapf(ErrInvalidProof, "child root index %d out of range [0, %d)", index, len(ar))
+	}
+
+	_, proofs := merkle.SimpleProofsFromByteSlices(ar.hashes())
+	return proofs[index].Aunts, nil
+}
+
+// hashes returns the hashes of the child roots.
+func (ar AggregateRoots) hashes() [][]byte {
+	hashes := make([][]byte, len(ar))
+	for i, root := range ar {
+		hashes[i] = root.GetHash()
+	}
+	return hashes
+}
+
+// VerifyChildRoot verifies that the child root is committed at the given index of
+// an aggregate root of total child roots, using the witness returned by
+// ProveChildRoot.
+func VerifyChildRoot(aggregate, child exported.Root, index, total int, witness [][]byte) error {
+	if aggregate == nil || aggregate.IsEmpty() || child == nil || child.IsEmpty() {
+		return sdkerrors.Wrap(ErrInvalidProof, "empty aggregate or child root")
+	}
+
+	// the simple merkle root of a single item is the leaf hash of the item
+	proof := merkle.SimpleProof{
+		Total:    total,
+		Index:    index,
+		LeafHash: merkle.SimpleHashFromByteSlices([][]byte{child.GetHash()}),
+		Aunts:    witness,
+	}
+
+	if err := proof.Verify(aggregate.GetHash(), child.GetHash()); err != nil {
+		return sdkerrors.Wrap(ErrInvalidProof, err.Error())
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package types_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tendermint/tendermint/crypto/tmhash"
+
+	"github.com/cosmos/cosmos-sdk/x/ibc/23-commitment/types"
+)
+
+func TestAggregateRoot(t *testing.T) {
+	roots := []types.MerkleRoot{
+		types.NewMerkleRoot(tmhash.Sum([]byte("chain-a"))),
+		types.NewMerkleRoot(tmhash.Sum([]byte("chain-b"))),
+		types.NewMerkleRoot(tmhash.Sum([]byte("chain-c"))),
+	}
+	aggregate := types.NewAggregateRoot(roots)
+	require.False(t, aggregate.IsEmpty())
+
+	// the order of the child roots is committed to
+	reordered := types.NewAggregateRoot([]types.MerkleRoot{roots[1], roots[0], roots[2]})
+	require.NotEqual(t, aggregate.GetHash(), reordered.GetHash())
+
+	for i := range roots {
+		witness, err := types.AggregateRoots(roots).ProveChildRoot(i)
+		require.NoError(t, err)
+
+		require.NoError(t, types.VerifyChildRoot(&aggregate, &roots[i], i, len(roots), witness), "child root %d", i)
+		require.Error(t, types.VerifyChildRoot(&reordered, &roots[i], i, len(roots), witness), "child root %d against wrong aggregate", i)
+		require.Error(t, types.VerifyChildRoot(&aggregate, &roots[(i+1)%len(roots)], i, len(roots), witness), "wrong child root %d", i)
+		require.Error(t, types.VerifyChildRoot(&aggregate, &roots[i], (i+1)%len(roots), len(roots), witness), "child root %d at wrong index", i)
+	}
+
+	_, err := types.AggregateRoots(roots).ProveChildRoot(len(roots))
+	require.Error(t, err)
+	_, err = types.AggregateRoots(roots).ProveChildRoot(-1)
+	require.Error(t, err)
+
+	emptyRoot := types.NewMerkleRoot(nil)
+	require.Error(t, types.VerifyChildRoot(&emptyRoot, &roots[0], 0, len(roots), nil))
+	require.Error(t, types.VerifyChildRoot(&aggregate, &emptyRoot, 0, len(roots), nil))
+}
@@ -0,0 +1,96 @@
+package types
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"github.com/cosmos/cosmos-sdk/store/rootmulti"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/cosmos/cosmos-sdk/x/ibc/23-commitment/exported"
+)
+
+// BatchItem is an item of a batch verification: the value committed at the path
+// of the item and the proof of its membership. A proof proves a single key, so
+// each item carries its own proof.
+type BatchItem struct {
+	Proof MerkleProof `json:"proof" yaml:"proof"`
+	Value []byte      `json:"value" yaml:"value"`
+}
+
+// VerifyBatchMembershipContext verifies the membership of each of the items
+// against the same root. The items are keyed by their escaped path (see
+// MerklePath.String) and verified in lexical order of their paths. The context
+// is checked before each item, so the caller can cap the total verification
+// time. The returned error includes the first item that failed, or the context
+// error, along with the number of items verified before it.
+func VerifyBatchMembershipContext(ctx context.Context, root exported.Root, items map[string]BatchItem) error {
+	if root == nil || root.IsEmpty() || len(items) == 0 {
+		return errors.New("empty params")
+	}
+
+	if isZeroHash(root.GetHash()) {
+		return errors.New("root hash cannot be zero")
+	}
+
+	paths := make([]string, 0, len(items))
+	for path := range items {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	runtime := rootmulti.DefaultProofRuntime()
+	for i, path := range paths {
+		if err := ctx.Err(); err != nil {
+			return sdkerrors.Wrapf(err, "verified %d of %d items", i, len(paths))
+		}
+
+		item := items[path]
+		if item.Proof.IsEmpty() || len(item.Value) == 0 {
+			return sdkerrors.Wrapf(
+				ErrInvalidProof, "empty proof or value for item %s, verified %d of %d items", path, i, len(paths),
+			)
+		}
+
+		if err := runtime.VerifyValue(item.Proof.Proof, root.GetHash(), path, item.Value); err != nil {
+			return sdkerrors.Wrapf(
+				ErrInvalidProof, "item %s failed, verified %d of %d items: %s", path, i, len(paths), err,
+			)
+		}
+	}
+
+	return nil
+}
+
+// VerifyBatchMembershipDetailed verifies the membership of each of the items
+// against the same root, as VerifyBatchMembershipContext, but doesn't stop at the
+// first failure: it returns the outcome of each item, keyed as the items, which
+// is nil if the item was verified. The error is only returned, without outcomes,
+// if the root or the items are empty.
+func VerifyBatchMembershipDetailed(root exported.Root, items map[string]BatchItem) (map[string]error, error) {
+	if root == nil || root.IsEmpty() || len(items) == 0 {
+		return nil, errors.New("empty params")
+	}
+
+	if isZeroHash(root.GetHash()) {
+		return nil, errors.New("root hash cannot be zero")
+	}
+
+	runtime := rootmulti.DefaultProofRuntime()
+	results := make(map[string]error, len(items))
+	for path, item := range items {
+		if item.Proof.IsEmpty() || len(item.Value) == 0 {
+			results[path] = sdkerrors.Wrapf(ErrInvalidProof, "empty proof or value for item %s", path)
+			continue
+		}
+
+		if err := runtime.VerifyValue(item.Proof.Proof, root.GetHash(), path, item.Value); err != nil {
+			results[path] = sdkerrors.Wrapf(ErrInvalidProof, "item %s failed: %s", path, err)
+			continue
+		}
+
+		results[path] = nil
+	}
+
+	return results, nil
+}
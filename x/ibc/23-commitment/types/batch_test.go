@@ -0,0 +1,176 @@
+package types_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/crypto/merkle"
+
+	"github.com/cosmos/cosmos-sdk/x/ibc/23-commitment/exported"
+	"github.com/cosmos/cosmos-sdk/x/ibc/23-commitment/types"
+)
+
+// countdownContext is a context that expires after its Err method has been
+// called a given number of times.
+type countdownContext struct {
+	context.Context
+	remaining int
+}
+
+func (ctx *countdownContext) Err() error {
+	if ctx.remaining <= 0 {
+		return context.DeadlineExceeded
+	}
+	ctx.remaining--
+	return nil
+}
+
+// batchItems commits the given keys and values in the IAVL store of the suite
+// and returns the commitment root and the items proving each of them, keyed by
+// their escaped path.
+func (suite *MerkleTestSuite) batchItems(values map[string]string) (types.MerkleRoot, map[string]types.BatchItem) {
+	for key, value := range values {
+		suite.iavlStore.Set([]byte(key), []byte(value))
+	}
+	cid := suite.store.Commit()
+
+	items := make(map[string]types.BatchItem, len(values))
+	for key, value := range values {
+		res := suite.store.Query(abci.RequestQuery{
+			Path:   fmt.Sprintf("/%s/key", suite.storeKey.Name()), // required path to get key/value+proof
+			Data:   []byte(key),
+			Height: cid.Version,
+			Prove:  true,
+		})
+		suite.Require().NotNil(res.Proof)
+
+		path := types.NewMerklePath([]string{suite.storeKey.Name(), key})
+		items[path.String()] = types.BatchItem{Proof: types.MerkleProof{Proof: res.Proof}, Value: []byte(value)}
+	}
+
+	return types.NewMerkleRoot(cid.Hash), items
+}
+
+func (suite *MerkleTestSuite) TestVerifyBatchMembershipContext() {
+	root, items := suite.batchItems(map[string]string{
+		"KEY1": "VALUE1",
+		"KEY2": "VALUE2",
+		"KEY3": "VALUE3",
+	})
+
+	// the items are verified in lexical order of their paths
+	withItem := func(path string, item types.BatchItem) map[string]types.BatchItem {
+		modified := make(map[string]types.BatchItem, len(items))
+		for p, i := range items {
+			modified[p] = i
+		}
+		modified[path] = item
+		return modified
+	}
+	wrongValue := items["/iavlStoreKey/KEY2"]
+	wrongValue.Value = []byte("WRONGVALUE")
+	emptyValue := items["/iavlStoreKey/KEY1"]
+	emptyValue.Value = nil
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	cases := []struct {
+		name       string
+		ctx        context.Context
+		items      map[string]types.BatchItem
+		expErrPart string
+		expPass    bool
+	}{
+		{"all items verified", context.Background(), items, "", true},
+		{"context expires mid-batch", &countdownContext{context.Background(), 2}, items, "verified 2 of 3 items", false},
+		{"context expired before the batch", cancelled, items, "verified 0 of 3 items", false},
+		{"wrong value", context.Background(), withItem("/iavlStoreKey/KEY2", wrongValue), "item /iavlStoreKey/KEY2 failed, verified 1 of 3 items", false},
+		{"proof of another key", context.Background(), withItem("/iavlStoreKey/KEY3", items["/iavlStoreKey/KEY1"]), "item /iavlStoreKey/KEY3 failed, verified 2 of 3 items", false},
+		{"empty value", context.Background(), withItem("/iavlStoreKey/KEY1", emptyValue), "verified 0 of 3 items", false},
+		{"empty proof", context.Background(), withItem("/iavlStoreKey/KEY2", types.BatchItem{Value: []byte("VALUE2")}), "verified 1 of 3 items", false},
+		{"no items", context.Background(), nil, "", false},
+	}
+
+	for i, tc := range cases {
+		tc := tc
+		suite.Run(tc.name, func() {
+			err := types.VerifyBatchMembershipContext(tc.ctx, &root, tc.items)
+
+			if tc.expPass {
+				// nolint: scopelint
+				suite.Require().NoError(err, "test case %d should have passed", i)
+			} else {
+				// nolint: scopelint
+				suite.Require().Error(err, "test case %d should have failed", i)
+				suite.Require().True(strings.Contains(err.Error(), tc.expErrPart), err.Error())
+			}
+		})
+	}
+
+	zeroRoot := types.NewMerkleRoot(make([]byte, 32))
+	suite.Require().Error(types.VerifyBatchMembershipContext(context.Background(), nil, items))
+	suite.Require().Error(types.VerifyBatchMembershipContext(context.Background(), &zeroRoot, items))
+}
+
+func (suite *MerkleTestSuite) TestVerifyBatchMembershipDetailed() {
+	root, items := suite.batchItems(map[string]string{
+		"KEY1": "VALUE1",
+		"KEY2": "VALUE2",
+		"KEY3": "VALUE3",
+		"KEY4": "VALUE4",
+	})
+
+	results, err := types.VerifyBatchMembershipDetailed(&root, items)
+	suite.Require().NoError(err)
+	suite.Require().Len(results, len(items))
+	for path, result := range results {
+		suite.Require().NoError(result, path)
+	}
+
+	// each failing item fails on its own, the other items still pass
+	wrongValue := items["/iavlStoreKey/KEY1"]
+	wrongValue.Value = []byte("WRONGVALUE")
+	emptyValue := items["/iavlStoreKey/KEY2"]
+	emptyValue.Value = nil
+	undecodable := types.BatchItem{
+		Proof: types.MerkleProof{Proof: &merkle.Proof{Ops: []merkle.ProofOp{{Type: "unknown", Key: []byte("KEY3")}}}},
+		Value: []byte("VALUE3"),
+	}
+
+	results, err = types.VerifyBatchMembershipDetailed(&root, map[string]types.BatchItem{
+		"/iavlStoreKey/KEY1": wrongValue,
+		"/iavlStoreKey/KEY2": emptyValue,
+		"/iavlStoreKey/KEY3": undecodable,
+		"/iavlStoreKey/KEY4": items["/iavlStoreKey/KEY4"],
+		"/iavlStoreKey/KEY5": items["/iavlStoreKey/KEY4"],
+	})
+	suite.Require().NoError(err)
+	suite.Require().Len(results, 5)
+	for _, path := range []string{"/iavlStoreKey/KEY1", "/iavlStoreKey/KEY2", "/iavlStoreKey/KEY3", "/iavlStoreKey/KEY5"} {
+		suite.Require().Error(results[path], path)
+		suite.Require().True(types.ErrInvalidProof.Is(results[path]), path)
+	}
+	suite.Require().NoError(results["/iavlStoreKey/KEY4"])
+
+	// structurally invalid arguments fail the whole batch
+	zeroRoot := types.NewMerkleRoot(make([]byte, 32))
+
+	cases := []struct {
+		name  string
+		root  exported.Root
+		items map[string]types.BatchItem
+	}{
+		{"no items", &root, nil},
+		{"nil root", nil, items},
+		{"zero root", &zeroRoot, items},
+	}
+
+	for i, tc := range cases {
+		results, err := types.VerifyBatchMembershipDetailed(tc.root, tc.items)
+		suite.Require().Error(err, "test case %d: %s", i, tc.name)
+		suite.Require().Nil(results, "test case %d: %s", i, tc.name)
+	}
+}
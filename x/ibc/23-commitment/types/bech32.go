@@ -0,0 +1,41 @@
+package types
+
+import (
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+// Bech32PrefixRoot defines the bech32 human readable part of merkle roots
+const Bech32PrefixRoot = "ibcroot"
+
+// Bech32 returns the bech32 encoding of the root hash, with the Bech32PrefixRoot
+// human readable part.
+//
+// NOTE: the encoding is intended for display and interchange only, roots are
+// committed and verified by their hash.
+func (mr MerkleRoot) Bech32() (string, error) {
+	if mr.IsEmpty() {
+		return "", sdkerrors.Wrap(ErrInvalidRoot, "root hash cannot be empty")
+	}
+
+	return bech32.ConvertAndEncode(Bech32PrefixRoot, mr.GetHash())
+}
+
+// MerkleRootFromBech32 decodes a merkle root from its bech32 encoding. It
+// returns an error if the human readable part isn't Bech32PrefixRoot.
+func MerkleRootFromBech32(s string) (MerkleRoot, error) {
+	hrp, hash, err := bech32.DecodeAndConvert(s)
+	if err != nil {
+		return MerkleRoot{}, sdkerrors.Wrap(ErrInvalidRoot, err.Error())
+	}
+
+	if hrp != Bech32PrefixRoot {
+		return MerkleRoot{}, sdkerrors.Wrapf(ErrInvalidRoot, "invalid bech32 prefix, expected %s, got %s", Bech32PrefixRoot, hrp)
+	}
+
+	if len(hash) == 0 {
+		return MerkleRoot{}, sdkerrors.Wrap(ErrInvalidRoot, "root hash cannot be empty")
+	}
+
+	return NewMerkleRoot(hash), nil
+}
@@ -0,0 +1,80 @@
+package types_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tendermint/tendermint/crypto/tmhash"
+
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+	"github.com/cosmos/cosmos-sdk/x/ibc/23-commitment/types"
+)
+
+func TestMerkleRootBech32(t *testing.T) {
+	testCases := []struct {
+		name    string
+		root    types.MerkleRoot
+		expPass bool
+	}{
+		{"hash", types.NewMerkleRoot(tmhash.Sum([]byte("root"))), true},
+		{"single byte hash", types.NewMerkleRoot([]byte{0x01}), true},
+		{"zero hash", types.NewMerkleRoot(make([]byte, tmhash.Size)), true},
+		{"empty hash", types.NewMerkleRoot(nil), false},
+	}
+
+	for _, tc := range testCases {
+		bech, err := tc.root.Bech32()
+		if !tc.expPass {
+			require.Error(t, err, tc.name)
+			continue
+		}
+
+		require.NoError(t, err, tc.name)
+		require.True(t, strings.HasPrefix(bech, types.Bech32PrefixRoot+"1"), tc.name)
+
+		root, err := types.MerkleRootFromBech32(bech)
+		require.NoError(t, err, tc.name)
+		require.Equal(t, tc.root, root, tc.name)
+	}
+}
+
+func TestMerkleRootFromBech32(t *testing.T) {
+	hash := tmhash.Sum([]byte("root"))
+	valid, err := types.NewMerkleRoot(hash).Bech32()
+	require.NoError(t, err)
+	wrongPrefix, err := bech32.ConvertAndEncode("cosmos", hash)
+	require.NoError(t, err)
+	empty, err := bech32.ConvertAndEncode(types.Bech32PrefixRoot, nil)
+	require.NoError(t, err)
+
+	// replace the last checksum character
+	last := "q"
+	if strings.HasSuffix(valid, last) {
+		last = "p"
+	}
+
+	testCases := []struct {
+		name    string
+		bech    string
+		expPass bool
+	}{
+		{"valid", valid, true},
+		{"wrong prefix", wrongPrefix, false},
+		{"empty hash", empty, false},
+		{"invalid checksum", valid[:len(valid)-1] + last, false},
+		{"hex root", "E3B0C44298FC1C149AFBF4C8996FB92427AE41E4649B934CA495991B7852B855", false},
+		{"empty string", "", false},
+	}
+
+	for _, tc := range testCases {
+		root, err := types.MerkleRootFromBech32(tc.bech)
+		if tc.expPass {
+			require.NoError(t, err, tc.name)
+			require.Equal(t, hash, root.GetHash(), tc.name)
+		} else {
+			require.Error(t, err, tc.name)
+			require.True(t, types.ErrInvalidRoot.Is(err), tc.name)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package types
+
+import (
+	"github.com/tendermint/iavl"
+	"github.com/tendermint/tendermint/crypto/merkle"
+
+	"github.com/cosmos/cosmos-sdk/store/rootmulti"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+// NewChainedProof constructs a MerkleProof from its levels. The levels must be
+// ordered from the lowest subtree up to the root: the first level is the single
+// store level proof (IAVL existence or absence), and every later level is a
+// multistore or simple merkle proof committing to the root of the level below.
+// Every level must be decodable by the default multistore proof runtime.
+func NewChainedProof(levels []merkle.ProofOp) (MerkleProof, error) {
+	if len(levels) == 0 {
+		return MerkleProof{}, sdkerrors.Wrap(ErrInvalidProof, "chained proof must contain at least one level")
+	}
+
+	runtime := rootmulti.DefaultProofRuntime()
+	for i, level := range levels {
+		if _, err := runtime.Decode(level); err != nil {
+			return MerkleProof{}, sdkerrors.Wrapf(ErrInvalidProof, "level %d: %s", i, err)
+		}
+
+		if err := checkChainedLevel(i, level.Type); err != nil {
+			return MerkleProof{}, err
+		}
+	}
+
+	ops := make([]merkle.ProofOp, len(levels))
+	copy(ops, levels)
+
+	return MerkleProof{
+		Proof: &merkle.Proof{Ops: ops},
+	}, nil
+}
+
+// checkChainedLevel returns an error if a proof of the given type can't be the
+// level at the given index of a chained proof: only the first level proves a
+// store key, the later levels prove the roots of the levels below.
+func checkChainedLevel(index int, opType string) error {
+	switch opType {
+	case iavl.ProofOpIAVLValue, iavl.ProofOpIAVLAbsence:
+		if index != 0 {
+			return sdkerrors.Wrapf(ErrInvalidProof, "level %d: %s proof must be the first level", index, opType)
+		}
+	case rootmulti.ProofOpMultiStore, merkle.ProofOpSimpleValue:
+		if index == 0 {
+			return sdkerrors.Wrapf(ErrInvalidProof, "level %d: %s proof cannot be the first level", index, opType)
+		}
+	default:
+		return sdkerrors.Wrapf(ErrInvalidProof, "level %d: unsupported proof type %s", index, opType)
+	}
+
+	return nil
+}
+
+// Levels splits the proof into its levels, returning for each proof operation a
+// single level MerkleProof, in order from the lowest subtree up to the root, so
+// that the levels can be verified independently (eg: in parallel).
+//
+// NOTE: each level only proves that its value is committed to its own subroot.
+// The caller must still check that the subroot of each level is the value of the
+// next one, and that the last subroot is the trusted root.
+func (proof MerkleProof) Levels() ([]MerkleProof, error) {
+	if proof.IsEmpty() {
+		return nil, sdkerrors.Wrap(ErrInvalidProof, "proof cannot be empty")
+	}
+
+	runtime := rootmulti.DefaultProofRuntime()
+	levels := make([]MerkleProof, len(proof.Proof.Ops))
+	for i, op := range proof.Proof.Ops {
+		if _, err := runtime.Decode(op); err != nil {
+			return nil, sdkerrors.Wrapf(ErrInvalidProof, "level %d: %s", i, err)
+		}
+
+		levels[i] = MerkleProof{
+			Proof: &merkle.Proof{Ops: []merkle.ProofOp{op}},
+		}
+	}
+
+	return levels, nil
+}
+
+// Subroots returns the subroot computed by each proof operation, in order from
+// the lowest subtree up to the root, so that they can be cross-checked against
+// independently recorded store roots. The last subroot is the root of the proof.
+//
+// NOTE: the proof is not verified: the subroots are only meaningful once the
+// proof has been verified against a trusted root.
+func (proof MerkleProof) Subroots() ([][]byte, error) {
+	if proof.IsEmpty() {
+		return nil, sdkerrors.Wrap(ErrInvalidProof, "proof cannot be empty")
+	}
+
+	runtime := rootmulti.DefaultProofRuntime()
+	poz, err := runtime.DecodeProof(proof.Proof)
+	if err != nil {
+		return nil, sdkerrors.Wrap(ErrInvalidProof, err.Error())
+	}
+
+	subroots := make([][]byte, len(poz))
+	for i, op := range poz {
+		facts, err := getOperatorFacts(op)
+		if err != nil {
+			return nil, sdkerrors.Wrapf(ErrInvalidProof, "level %d: %s", i, err)
+		}
+		subroots[i] = facts.subroot
+	}
+
+	return subroots, nil
+}
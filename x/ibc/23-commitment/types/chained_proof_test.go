@@ -0,0 +1,191 @@
+package types_test
+
+import (
+	"fmt"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/crypto/merkle"
+
+	"github.com/cosmos/cosmos-sdk/x/ibc/23-commitment/types"
+)
+
+func (suite *MerkleTestSuite) TestNewChainedProof() {
+	suite.iavlStore.Set([]byte("MYKEY"), []byte("MYVALUE"))
+	cid := suite.store.Commit()
+
+	res := suite.store.Query(abci.RequestQuery{
+		Path:  fmt.Sprintf("/%s/key", suite.storeKey.Name()), // required path to get key/value+proof
+		Data:  []byte("MYKEY"),
+		Prove: true,
+	})
+	suite.Require().NotNil(res.Proof)
+	suite.Require().Len(res.Proof.Ops, 2)
+
+	storeOp, multiStoreOp := res.Proof.Ops[0], res.Proof.Ops[1]
+
+	// wrap the multistore root in a third, simple merkle level
+	_, simpleProofs, _ := merkle.SimpleProofsFromMap(map[string][]byte{
+		"app":   cid.Hash,
+		"other": []byte("OTHERROOT"),
+	})
+	simpleOp := merkle.NewSimpleValueOp([]byte("app"), simpleProofs["app"]).ProofOp()
+
+	cases := []struct {
+		name    string
+		levels  []merkle.ProofOp
+		expPass bool
+	}{
+		{"store and multistore levels", []merkle.ProofOp{storeOp, multiStoreOp}, true},
+		{"store level only", []merkle.ProofOp{storeOp}, true},
+		{"store, multistore and simple levels", []merkle.ProofOp{storeOp, multiStoreOp, simpleOp}, true},
+		{"stacked multistore levels", []merkle.ProofOp{storeOp, multiStoreOp, multiStoreOp}, true},
+		{"misordered levels", []merkle.ProofOp{multiStoreOp, storeOp}, false},
+		{"misordered simple level", []merkle.ProofOp{storeOp, simpleOp, storeOp}, false},
+		{"multistore level only", []merkle.ProofOp{multiStoreOp}, false},
+		{"simple level only", []merkle.ProofOp{simpleOp}, false},
+		{"duplicated store level", []merkle.ProofOp{storeOp, storeOp}, false},
+		{"duplicated store level above multistore", []merkle.ProofOp{storeOp, multiStoreOp, storeOp}, false},
+		{"unknown level type", []merkle.ProofOp{{Type: "unknown", Key: storeOp.Key, Data: storeOp.Data}, multiStoreOp}, false},
+		{"undecodable level", []merkle.ProofOp{{Type: storeOp.Type, Key: storeOp.Key, Data: []byte("invalid")}, multiStoreOp}, false},
+		{"no levels", nil, false},
+	}
+
+	for i, tc := range cases {
+		tc := tc
+		suite.Run(tc.name, func() {
+			proof, err := types.NewChainedProof(tc.levels)
+
+			if tc.expPass {
+				// nolint: scopelint
+				suite.Require().NoError(err, "test case %d should have passed", i)
+				suite.Require().Equal(tc.levels, proof.Proof.Ops)
+			} else {
+				// nolint: scopelint
+				suite.Require().Error(err, "test case %d should have failed", i)
+				suite.Require().True(proof.IsEmpty())
+			}
+		})
+	}
+
+	// the proof constructed from both levels verifies against the multistore root
+	proof, err := types.NewChainedProof([]merkle.ProofOp{storeOp, multiStoreOp})
+	suite.Require().NoError(err)
+
+	root := types.NewMerkleRoot(cid.Hash)
+	path := types.NewMerklePath([]string{suite.storeKey.Name(), "MYKEY"})
+	suite.Require().NoError(proof.VerifyMembership(&root, path, []byte("MYVALUE")))
+}
+
+func (suite *MerkleTestSuite) TestLevels() {
+	suite.iavlStore.Set([]byte("MYKEY"), []byte("MYVALUE"))
+	cid := suite.store.Commit()
+	storeRoot := suite.iavlStore.LastCommitID().Hash
+
+	res := suite.store.Query(abci.RequestQuery{
+		Path:   fmt.Sprintf("/%s/key", suite.storeKey.Name()), // required path to get key/value+proof
+		Data:   []byte("MYKEY"),
+		Height: cid.Version,
+		Prove:  true,
+	})
+	suite.Require().NotNil(res.Proof)
+	suite.Require().Len(res.Proof.Ops, 2)
+
+	// wrap the multistore root in a third, simple merkle level
+	topRoot, simpleProofs, _ := merkle.SimpleProofsFromMap(map[string][]byte{
+		"app":   cid.Hash,
+		"other": []byte("OTHERROOT"),
+	})
+	topOp := merkle.NewSimpleValueOp([]byte("app"), simpleProofs["app"]).ProofOp()
+
+	proof := types.MerkleProof{
+		Proof: &merkle.Proof{Ops: []merkle.ProofOp{res.Proof.Ops[0], res.Proof.Ops[1], topOp}},
+	}
+
+	root := types.NewMerkleRoot(topRoot)
+	path := types.NewMerklePath([]string{"app", suite.storeKey.Name(), "MYKEY"})
+	suite.Require().NoError(proof.VerifyMembership(&root, path, []byte("MYVALUE")))
+
+	levels, err := proof.Levels()
+	suite.Require().NoError(err)
+	suite.Require().Len(levels, 3)
+
+	// each level verifies independently against its own subroot
+	expLevels := []struct {
+		root  []byte
+		key   string
+		value []byte
+	}{
+		{storeRoot, "MYKEY", []byte("MYVALUE")},
+		{cid.Hash, suite.storeKey.Name(), storeRoot},
+		{topRoot, "app", cid.Hash},
+	}
+
+	for i, exp := range expLevels {
+		suite.Require().Len(levels[i].Proof.Ops, 1)
+		suite.Require().Equal(proof.Proof.Ops[i], levels[i].Proof.Ops[0])
+
+		levelRoot := types.NewMerkleRoot(exp.root)
+		levelPath := types.NewMerklePath([]string{exp.key})
+		suite.Require().NoError(levels[i].VerifyMembership(&levelRoot, levelPath, exp.value), "level %d", i)
+	}
+
+	// a level only verifies against its own subroot
+	wrongRoot := types.NewMerkleRoot(cid.Hash)
+	suite.Require().Error(levels[0].VerifyMembership(&wrongRoot, types.NewMerklePath([]string{"MYKEY"}), []byte("MYVALUE")))
+
+	_, err = types.MerkleProof{}.Levels()
+	suite.Require().Error(err)
+
+	undecodable := types.MerkleProof{Proof: &merkle.Proof{Ops: []merkle.ProofOp{res.Proof.Ops[0], {Type: "unknown"}}}}
+	_, err = undecodable.Levels()
+	suite.Require().Error(err)
+}
+
+func (suite *MerkleTestSuite) TestSubroots() {
+	suite.iavlStore.Set([]byte("MYKEY"), []byte("MYVALUE"))
+	cid := suite.store.Commit()
+	storeRoot := suite.iavlStore.LastCommitID().Hash
+
+	res := suite.store.Query(abci.RequestQuery{
+		Path:   fmt.Sprintf("/%s/key", suite.storeKey.Name()), // required path to get key/value+proof
+		Data:   []byte("MYKEY"),
+		Height: cid.Version,
+		Prove:  true,
+	})
+	suite.Require().NotNil(res.Proof)
+	suite.Require().Len(res.Proof.Ops, 2)
+
+	// wrap the multistore root in a third, simple merkle level
+	topRoot, simpleProofs, _ := merkle.SimpleProofsFromMap(map[string][]byte{
+		"app":   cid.Hash,
+		"other": []byte("OTHERROOT"),
+	})
+	topOp := merkle.NewSimpleValueOp([]byte("app"), simpleProofs["app"]).ProofOp()
+
+	proof := types.MerkleProof{
+		Proof: &merkle.Proof{Ops: []merkle.ProofOp{res.Proof.Ops[0], res.Proof.Ops[1], topOp}},
+	}
+
+	root := types.NewMerkleRoot(topRoot)
+	path := types.NewMerklePath([]string{"app", suite.storeKey.Name(), "MYKEY"})
+	suite.Require().NoError(proof.VerifyMembership(&root, path, []byte("MYVALUE")))
+
+	subroots, err := proof.Subroots()
+	suite.Require().NoError(err)
+	suite.Require().Equal([][]byte{storeRoot, cid.Hash, topRoot}, subroots)
+
+	cases := []struct {
+		name  string
+		proof types.MerkleProof
+	}{
+		{"empty proof", types.MerkleProof{}},
+		{"unknown level type", types.MerkleProof{Proof: &merkle.Proof{Ops: []merkle.ProofOp{res.Proof.Ops[0], {Type: "unknown"}}}}},
+		{"undecodable level", types.MerkleProof{Proof: &merkle.Proof{Ops: []merkle.ProofOp{{Type: res.Proof.Ops[0].Type, Data: []byte("invalid")}}}}},
+	}
+
+	for i, tc := range cases {
+		subroots, err := tc.proof.Subroots()
+		suite.Require().Error(err, "test case %d: %s", i, tc.name)
+		suite.Require().Nil(subroots, "test case %d: %s", i, tc.name)
+	}
+}
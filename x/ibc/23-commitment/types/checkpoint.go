@@ -0,0 +1,20 @@
+package types
+
+import (
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/cosmos/cosmos-sdk/x/ibc/23-commitment/exported"
+)
+
+// VerifyAtCheckpoint verifies the membership of a merkle proof against the root
+// checkpointed at the given height, rather than the latest root. It fails if no
+// checkpoint was written at that height.
+func (proof MerkleProof) VerifyAtCheckpoint(
+	checkpoints map[uint64]MerkleRoot, checkpointHeight uint64, path exported.Path, value []byte,
+) error {
+	root, ok := checkpoints[checkpointHeight]
+	if !ok {
+		return sdkerrors.Wrapf(ErrInvalidRoot, "height %d is not a checkpoint", checkpointHeight)
+	}
+
+	return proof.VerifyMembership(&root, path, value)
+}
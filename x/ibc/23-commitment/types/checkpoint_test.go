@@ -0,0 +1,67 @@
+package types_test
+
+import (
+	"fmt"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+
+	"github.com/cosmos/cosmos-sdk/x/ibc/23-commitment/types"
+)
+
+func (suite *MerkleTestSuite) TestVerifyAtCheckpoint() {
+	suite.iavlStore.Set([]byte("MYKEY"), []byte("MYVALUE"))
+	checkpointCid := suite.store.Commit()
+
+	res := suite.store.Query(abci.RequestQuery{
+		Path:   fmt.Sprintf("/%s/key", suite.storeKey.Name()), // required path to get key/value+proof
+		Data:   []byte("MYKEY"),
+		Height: checkpointCid.Version,
+		Prove:  true,
+	})
+	suite.Require().NotNil(res.Proof)
+
+	// the tip moves on after the checkpoint
+	suite.iavlStore.Set([]byte("MYKEY"), []byte("MYNEWVALUE"))
+	tipCid := suite.store.Commit()
+
+	proof := types.MerkleProof{
+		Proof: res.Proof,
+	}
+	path := types.NewMerklePath([]string{suite.storeKey.Name(), "MYKEY"})
+	checkpoints := map[uint64]types.MerkleRoot{
+		uint64(checkpointCid.Version): types.NewMerkleRoot(checkpointCid.Hash),
+		uint64(tipCid.Version) + 1:    types.NewMerkleRoot(tipCid.Hash),
+	}
+
+	cases := []struct {
+		name        string
+		checkpoints map[uint64]types.MerkleRoot
+		height      uint64
+		value       []byte
+		expPass     bool
+	}{
+		{"checkpoint hit", checkpoints, uint64(checkpointCid.Version), []byte("MYVALUE"), true},
+		{"checkpoint hit with wrong value", checkpoints, uint64(checkpointCid.Version), []byte("MYNEWVALUE"), false},
+		{"checkpoint of another root", checkpoints, uint64(tipCid.Version) + 1, []byte("MYVALUE"), false},
+		{"height is not a checkpoint", checkpoints, uint64(tipCid.Version), []byte("MYVALUE"), false},
+		{"no checkpoints", nil, uint64(checkpointCid.Version), []byte("MYVALUE"), false},
+	}
+
+	for i, tc := range cases {
+		tc := tc
+		suite.Run(tc.name, func() {
+			err := proof.VerifyAtCheckpoint(tc.checkpoints, tc.height, path, tc.value)
+
+			if tc.expPass {
+				// nolint: scopelint
+				suite.Require().NoError(err, "test case %d should have passed", i)
+			} else {
+				// nolint: scopelint
+				suite.Require().Error(err, "test case %d should have failed", i)
+			}
+		})
+	}
+
+	err := proof.VerifyAtCheckpoint(checkpoints, uint64(tipCid.Version), path, []byte("MYVALUE"))
+	suite.Require().True(types.ErrInvalidRoot.Is(err))
+}
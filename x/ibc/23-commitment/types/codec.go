@@ -18,6 +18,7 @@ func RegisterCodec(cdc *codec.Codec) {
 	cdc.RegisterConcrete(MerklePrefix{}, "ibc/commitment/MerklePrefix", nil)
 	cdc.RegisterConcrete(MerklePath{}, "ibc/commitment/MerklePath", nil)
 	cdc.RegisterConcrete(MerkleProof{}, "ibc/commitment/MerkleProof", nil)
+	cdc.RegisterConcrete(MMRProof{}, "ibc/commitment/MMRProof", nil)
 }
 
 var (
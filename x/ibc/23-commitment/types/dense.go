@@ -0,0 +1,230 @@
+package types
+
+import (
+	"bytes"
+	"errors"
+	"strconv"
+
+	"github.com/tendermint/iavl"
+	"github.com/tendermint/tendermint/crypto/merkle"
+
+	"github.com/cosmos/cosmos-sdk/store/rootmulti"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/cosmos/cosmos-sdk/x/ibc/23-commitment/exported"
+	host "github.com/cosmos/cosmos-sdk/x/ibc/24-host"
+)
+
+// VerifyNonMembershipDense verifies the absence of the key of the given path in
+// a densely numbered key space, such as packet sequences, where the key ends with
+// a decimal number. On top of the checks of VerifyNonMembership, the neighbor
+// paths must share the key prefix of the path and bracket its number, and the
+// proof must commit to both neighbors as adjacent leaves of the tree, which
+// proves that no key exists between them.
+//
+// NOTE: the numeric and the byte-wise ordering of the keys must agree on the
+// bracketed interval (eg: numbers of the same width) for the adjacency of the
+// neighbors to prove the absence of every number between them.
+func (proof MerkleProof) VerifyNonMembershipDense(root exported.Root, path, leftNeighbor, rightNeighbor exported.Path) error {
+	if path == nil || leftNeighbor == nil || rightNeighbor == nil {
+		return errors.New("empty params or proof")
+	}
+
+	key, err := denseKey(path, nil)
+	if err != nil {
+		return err
+	}
+	left, err := denseKey(leftNeighbor, key)
+	if err != nil {
+		return sdkerrors.Wrap(err, "left neighbor")
+	}
+	right, err := denseKey(rightNeighbor, key)
+	if err != nil {
+		return sdkerrors.Wrap(err, "right neighbor")
+	}
+
+	if left.number >= key.number || key.number >= right.number {
+		return sdkerrors.Wrapf(
+			ErrInvalidAbsenceProof, "neighbors %d and %d don't bracket %d", left.number, key.number, right.number,
+		)
+	}
+
+	if err := proof.VerifyNonMembership(root, path); err != nil {
+		return err
+	}
+
+	// the absence operation has been decoded by VerifyNonMembership
+	op, _ := rootmulti.DefaultProofRuntime().Decode(proof.Proof.Ops[0])
+	var leaves []iavl.ProofLeafNode
+	if rangeProof := op.(iavl.AbsenceOp).Proof; rangeProof != nil {
+		leaves = rangeProof.Leaves
+	}
+
+	for i := 0; i+1 < len(leaves); i++ {
+		if bytes.Equal(leaves[i].Key, left.key) && bytes.Equal(leaves[i+1].Key, right.key) {
+			return nil
+		}
+	}
+
+	return sdkerrors.Wrapf(
+		ErrInvalidAbsenceProof, "neighbors %s and %s are not adjacent leaves of the proof", left.key, right.key,
+	)
+}
+
+// denseKeyPath defines a path of a densely numbered key space, split into the
+// keys of the path and the number its last key ends with.
+type denseKeyPath struct {
+	keys   [][]byte // keys of the path, the last one is the store key
+	key    []byte   // last key of the path
+	prefix []byte   // last key without its number
+	number uint64
+}
+
+// denseKey parses the given path of a densely numbered key space. If a reference
+// path is provided, the path must only differ from it by the number of its last
+// key.
+func denseKey(path exported.Path, ref *denseKeyPath) (*denseKeyPath, error) {
+	keys, err := merkle.KeyPathToKeys(path.String())
+	if err != nil || len(keys) == 0 {
+		return nil, sdkerrors.Wrapf(ErrInvalidAbsenceProof, "invalid path %s", path)
+	}
+
+	key := keys[len(keys)-1]
+	i := len(key)
+	for i > 0 && key[i-1] >= '0' && key[i-1] <= '9' {
+		i--
+	}
+
+	number, err := strconv.ParseUint(string(key[i:]), 10, 64)
+	if err != nil {
+		return nil, sdkerrors.Wrapf(ErrInvalidAbsenceProof, "key %s doesn't end with a number", key)
+	}
+
+	dkp := &denseKeyPath{keys: keys, key: key, prefix: key[:i], number: number}
+	if ref == nil {
+		return dkp, nil
+	}
+
+	if len(keys) != len(ref.keys) || !bytes.Equal(dkp.prefix, ref.prefix) {
+		return nil, sdkerrors.Wrapf(ErrInvalidAbsenceProof, "path %s is not in the key space of %s", path, ref.prefix)
+	}
+	for j := range keys[:len(keys)-1] {
+		if !bytes.Equal(keys[j], ref.keys[j]) {
+			return nil, sdkerrors.Wrapf(ErrInvalidAbsenceProof, "path %s is not in the key space of %s", path, ref.prefix)
+		}
+	}
+
+	return dkp, nil
+}
+
+// VerifyRangeExclusion verifies that the IBC store commits no packet of the
+// given channel with a sequence in the inclusive range [startSeq, endSeq] (eg:
+// before a clean channel close). The proof must prove the absence of the packet
+// commitment of the start sequence, as VerifyNonMembership, with leaves that
+// cover the whole range: since the leaves of an IAVL proof are adjacent in the
+// tree, no packet of the range exists if none of the leaves is one.
+//
+// NOTE: sequences are committed in decimal, so the range spans the byte-wise
+// interval between the lowest and the highest formatted sequence in it (eg:
+// [5, 12] spans "10" to "9"), which the leaves must cover.
+func (proof MerkleProof) VerifyRangeExclusion(root exported.Root, startSeq, endSeq uint64, portID, channelID string) error {
+	if startSeq > endSeq {
+		return sdkerrors.Wrapf(ErrInvalidAbsenceProof, "invalid sequence range [%d, %d]", startSeq, endSeq)
+	}
+
+	path := PacketCommitmentPath(portID, channelID, startSeq)
+	if _, _, err := path.PortChannel(); err != nil {
+		return sdkerrors.Wrap(ErrInvalidAbsenceProof, err.Error())
+	}
+
+	if err := proof.VerifyNonMembership(root, path); err != nil {
+		return err
+	}
+
+	// the absence operation has been decoded by VerifyNonMembership
+	op, _ := rootmulti.DefaultProofRuntime().Decode(proof.Proof.Ops[0])
+	rangeProof := op.(iavl.AbsenceOp).Proof
+	if rangeProof == nil {
+		// no packet exists in an empty tree
+		return nil
+	}
+
+	// the packet keys of the channel are the commitment key of the sequence 0
+	// without its sequence
+	keyPrefix := host.PacketCommitmentPath(portID, channelID, 0)
+	keyPrefix = keyPrefix[:len(keyPrefix)-1]
+	low := []byte(keyPrefix + minSequenceString(startSeq, endSeq))
+	high := []byte(keyPrefix + maxSequenceString(startSeq, endSeq))
+
+	leaves := rangeProof.Leaves
+	if bytes.Compare(leaves[0].Key, low) > 0 && rangeProof.LeftIndex() != 0 {
+		return sdkerrors.Wrapf(ErrInvalidAbsenceProof, "proof leaves don't cover the start of the range %s", low)
+	}
+	if bytes.Compare(leaves[len(leaves)-1].Key, high) < 0 && rangeProof.LeftIndex()+int64(len(leaves)) != iavlTreeSize(rangeProof) {
+		return sdkerrors.Wrapf(ErrInvalidAbsenceProof, "proof leaves don't cover the end of the range %s", high)
+	}
+
+	for _, leaf := range leaves {
+		sequence, ok := packetSequence(leaf.Key, keyPrefix)
+		if ok && startSeq <= sequence && sequence <= endSeq {
+			return sdkerrors.Wrapf(ErrInvalidAbsenceProof, "packet %d exists in the range [%d, %d]", sequence, startSeq, endSeq)
+		}
+	}
+
+	return nil
+}
+
+// packetSequence returns the sequence of the given packet key and true if the
+// key is the key of a packet under the given key prefix.
+func packetSequence(key []byte, keyPrefix string) (uint64, bool) {
+	if !bytes.HasPrefix(key, []byte(keyPrefix)) {
+		return 0, false
+	}
+
+	formatted := string(key[len(keyPrefix):])
+	sequence, err := strconv.ParseUint(formatted, 10, 64)
+	if err != nil || strconv.FormatUint(sequence, 10) != formatted {
+		return 0, false
+	}
+	return sequence, true
+}
+
+// minSequenceString returns the byte-wise lowest decimal formatted sequence of
+// the range [start, end]: the start sequence, or the lowest power of ten with
+// more digits if it is in the range and sorts before the start sequence.
+func minSequenceString(start, end uint64) string {
+	formatted := strconv.FormatUint(start, 10)
+
+	// 10^20 overflows a uint64
+	if len(formatted) < 20 {
+		power := uint64(1)
+		for range formatted {
+			power *= 10
+		}
+		if powerFormatted := strconv.FormatUint(power, 10); power <= end && powerFormatted < formatted {
+			return powerFormatted
+		}
+	}
+
+	return formatted
+}
+
+// maxSequenceString returns the byte-wise highest decimal formatted sequence of
+// the range [start, end]: the end sequence, or the highest number of nines with
+// less digits if it is in the range and sorts after the end sequence.
+func maxSequenceString(start, end uint64) string {
+	formatted := strconv.FormatUint(end, 10)
+	if len(formatted) == 1 {
+		return formatted
+	}
+
+	nines := uint64(1)
+	for range formatted[1:] {
+		nines *= 10
+	}
+	nines--
+
+	if ninesFormatted := strconv.FormatUint(nines, 10); nines >= start && ninesFormatted > formatted {
+		return ninesFormatted
+	}
+	return formatted
+}
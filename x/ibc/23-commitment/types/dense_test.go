@@ -0,0 +1,147 @@
+package types_test
+
+import (
+	"fmt"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+	dbm "github.com/tendermint/tm-db"
+
+	"github.com/cosmos/cosmos-sdk/store/rootmulti"
+	storetypes "github.com/cosmos/cosmos-sdk/store/types"
+	"github.com/cosmos/cosmos-sdk/x/ibc/23-commitment/types"
+	host "github.com/cosmos/cosmos-sdk/x/ibc/24-host"
+)
+
+func (suite *MerkleTestSuite) TestVerifyNonMembershipDense() {
+	// sequences 1 to 9, except for the gap of sequences 6 and 7
+	for _, sequence := range []int{1, 2, 3, 4, 5, 8, 9} {
+		suite.iavlStore.Set([]byte(fmt.Sprintf("seqs/%d", sequence)), []byte("MYVALUE"))
+	}
+	suite.iavlStore.Set([]byte("other/6"), []byte("MYVALUE"))
+	cid := suite.store.Commit()
+	root := types.NewMerkleRoot(cid.Hash)
+
+	query := func(key string) types.MerkleProof {
+		res := suite.store.Query(abci.RequestQuery{
+			Path:   fmt.Sprintf("/%s/key", suite.storeKey.Name()), // required path to get key/value+proof
+			Data:   []byte(key),
+			Height: cid.Version,
+			Prove:  true,
+		})
+		suite.Require().NotNil(res.Proof)
+		return types.MerkleProof{Proof: res.Proof}
+	}
+
+	path := func(key string) types.MerklePath {
+		return types.NewMerklePath([]string{suite.storeKey.Name(), key})
+	}
+
+	cases := []struct {
+		name    string
+		key     string
+		left    types.MerklePath
+		right   types.MerklePath
+		expPass bool
+	}{
+		{"start of interior gap", "seqs/6", path("seqs/5"), path("seqs/8"), true},
+		{"end of interior gap", "seqs/7", path("seqs/5"), path("seqs/8"), true},
+		{"non adjacent left neighbor", "seqs/6", path("seqs/4"), path("seqs/8"), false},
+		{"non adjacent right neighbor", "seqs/6", path("seqs/5"), path("seqs/9"), false},
+		{"absent right neighbor", "seqs/6", path("seqs/5"), path("seqs/7"), false},
+		{"swapped neighbors", "seqs/6", path("seqs/8"), path("seqs/5"), false},
+		{"left neighbor is the key", "seqs/6", path("seqs/6"), path("seqs/8"), false},
+		{"existing key", "seqs/5", path("seqs/4"), path("seqs/8"), false},
+		{"before the first sequence", "seqs/0", path("seqs/0"), path("seqs/1"), false},
+		{"after the last sequence", "seqs/10", path("seqs/9"), path("seqs/11"), false},
+		{"neighbor in another key space", "seqs/6", path("other/5"), path("seqs/8"), false},
+		{"neighbor in another store", "seqs/6", types.NewMerklePath([]string{"otherStoreKey", "seqs/5"}), path("seqs/8"), false},
+		{"non numeric key", "seqs/six", path("seqs/5"), path("seqs/8"), false},
+		{"empty neighbor", "seqs/6", types.MerklePath{}, path("seqs/8"), false},
+	}
+
+	for i, tc := range cases {
+		tc := tc
+		suite.Run(tc.name, func() {
+			err := query(tc.key).VerifyNonMembershipDense(&root, path(tc.key), tc.left, tc.right)
+
+			if tc.expPass {
+				// nolint: scopelint
+				suite.Require().NoError(err, "test case %d should have passed", i)
+			} else {
+				// nolint: scopelint
+				suite.Require().Error(err, "test case %d should have failed", i)
+			}
+		})
+	}
+
+	// a proof of the absence of another key doesn't prove the gap
+	err := query("seqs/7").VerifyNonMembershipDense(&root, path("seqs/6"), path("seqs/5"), path("seqs/8"))
+	suite.Require().Error(err)
+}
+
+func (suite *MerkleTestSuite) TestVerifyRangeExclusion() {
+	// packet commitments are proven against the IBC store
+	store := rootmulti.NewStore(dbm.NewMemDB())
+	storeKey := storetypes.NewKVStoreKey(host.StoreKey)
+	store.MountStoreWithDB(storeKey, storetypes.StoreTypeIAVL, nil)
+	suite.Require().NoError(store.LoadVersion(0))
+
+	// packets 1 to 3, 8, 9 and 20 of the channel, and packet 5 of another channel
+	ibcStore := store.GetCommitKVStore(storeKey)
+	for _, sequence := range []uint64{1, 2, 3, 8, 9, 20} {
+		ibcStore.Set(host.KeyPacketCommitment("portone", "channelzero", sequence), []byte("MYCOMMITMENT"))
+	}
+	ibcStore.Set(host.KeyPacketCommitment("portone", "channelone", 5), []byte("MYCOMMITMENT"))
+	cid := store.Commit()
+	root := types.NewMerkleRoot(cid.Hash)
+
+	query := func(channelID string, sequence uint64) types.MerkleProof {
+		res := store.Query(abci.RequestQuery{
+			Path:   fmt.Sprintf("/%s/key", storeKey.Name()), // required path to get key/value+proof
+			Data:   host.KeyPacketCommitment("portone", channelID, sequence),
+			Height: cid.Version,
+			Prove:  true,
+		})
+		suite.Require().NotNil(res.Proof)
+		return types.MerkleProof{Proof: res.Proof}
+	}
+
+	cases := []struct {
+		name      string
+		proof     types.MerkleProof
+		startSeq  uint64
+		endSeq    uint64
+		portID    string
+		channelID string
+		expPass   bool
+	}{
+		{"empty range", query("channelzero", 4), 4, 7, "portone", "channelzero", true},
+		{"single sequence range", query("channelzero", 6), 6, 6, "portone", "channelzero", true},
+		{"range with longer sequences", query("channelzero", 21), 21, 29, "portone", "channelzero", true},
+		{"packet sequence of another channel", query("channelzero", 5), 5, 5, "portone", "channelzero", true},
+		{"range containing one packet", query("channelzero", 4), 4, 8, "portone", "channelzero", false},
+		{"range starting on a packet", query("channelzero", 3), 3, 7, "portone", "channelzero", false},
+		{"range of another channel containing one packet", query("channelone", 4), 4, 7, "portone", "channelone", false},
+		{"range not covered by the proof", query("channelzero", 4), 4, 12, "portone", "channelzero", false},
+		{"proof of another sequence", query("channelzero", 5), 4, 7, "portone", "channelzero", false},
+		{"proof of another channel", query("channelone", 4), 4, 7, "portone", "channelzero", false},
+		{"inverted range", query("channelzero", 7), 7, 4, "portone", "channelzero", false},
+		{"invalid channel identifier", query("channelzero", 4), 4, 7, "portone", "(channelzero)", false},
+		{"empty proof", types.MerkleProof{}, 4, 7, "portone", "channelzero", false},
+	}
+
+	for i, tc := range cases {
+		tc := tc
+		suite.Run(tc.name, func() {
+			err := tc.proof.VerifyRangeExclusion(&root, tc.startSeq, tc.endSeq, tc.portID, tc.channelID)
+
+			if tc.expPass {
+				// nolint: scopelint
+				suite.Require().NoError(err, "test case %d should have passed", i)
+			} else {
+				// nolint: scopelint
+				suite.Require().Error(err, "test case %d should have failed", i)
+			}
+		})
+	}
+}
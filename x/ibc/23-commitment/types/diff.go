@@ -0,0 +1,94 @@
+package types
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/cosmos/cosmos-sdk/store/rootmulti"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+// DiffRoots returns the names of the substores whose roots differ between the
+// multistore roots a and b, sorted. The proofs are keyed by substore name and
+// must be proofs of keys of that substore against their respective root, as
+// queried from the rootmulti store: the root of each substore is the one the
+// top level multistore proof commits to.
+//
+// CONTRACT: both proof maps must hold a proof for the same substores.
+func DiffRoots(a, b MerkleRoot, aProofs, bProofs map[string]MerkleProof) ([]string, error) {
+	if len(aProofs) != len(bProofs) {
+		return nil, sdkerrors.Wrapf(ErrInvalidProof, "proofs for a different number of substores (%d ≠ %d)", len(aProofs), len(bProofs))
+	}
+
+	names := make([]string, 0, len(aProofs))
+	for name := range aProofs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var diff []string
+	for _, name := range names {
+		bProof, ok := bProofs[name]
+		if !ok {
+			return nil, sdkerrors.Wrapf(ErrInvalidProof, "no proof for substore %s against the second root", name)
+		}
+
+		aSubroot, err := substoreRoot(a, name, aProofs[name])
+		if err != nil {
+			return nil, sdkerrors.Wrap(err, "first root")
+		}
+		bSubroot, err := substoreRoot(b, name, bProof)
+		if err != nil {
+			return nil, sdkerrors.Wrap(err, "second root")
+		}
+
+		if !bytes.Equal(aSubroot, bSubroot) {
+			diff = append(diff, name)
+		}
+	}
+
+	return diff, nil
+}
+
+// substoreRoot returns the root of the named substore, as committed by the top
+// level multistore operation of the proof, after checking that the operation
+// computes the given multistore root.
+func substoreRoot(root MerkleRoot, name string, proof MerkleProof) ([]byte, error) {
+	if proof.IsEmpty() {
+		return nil, sdkerrors.Wrapf(ErrInvalidProof, "empty proof for substore %s", name)
+	}
+
+	ops := proof.Proof.Ops
+	operator, err := rootmulti.MultiStoreProofOpDecoder(ops[len(ops)-1])
+	if err != nil {
+		return nil, sdkerrors.Wrapf(ErrInvalidProof, "substore %s: %s", name, err)
+	}
+
+	op := operator.(rootmulti.MultiStoreProofOp)
+	if string(op.GetKey()) != name {
+		return nil, sdkerrors.Wrapf(ErrInvalidProof, "proof of substore %s keyed by %s", name, op.GetKey())
+	}
+
+	if op.Proof == nil {
+		return nil, sdkerrors.Wrapf(ErrInvalidProof, "empty multistore proof for substore %s", name)
+	}
+
+	for _, info := range op.Proof.StoreInfos {
+		if info.Name != name {
+			continue
+		}
+
+		subroot := info.Core.CommitID.Hash
+		out, err := op.Run([][]byte{subroot})
+		if err != nil {
+			return nil, sdkerrors.Wrapf(ErrInvalidProof, "substore %s: %s", name, err)
+		}
+		if !bytes.Equal(out[0], root.GetHash()) {
+			return nil, sdkerrors.Wrapf(ErrInvalidProof, "proof of substore %s computes root %X, expected %X", name, out[0], root.GetHash())
+		}
+
+		return subroot, nil
+	}
+
+	return nil, sdkerrors.Wrapf(ErrInvalidProof, "substore %s not found in multistore proof", name)
+}
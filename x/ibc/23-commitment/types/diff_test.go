@@ -0,0 +1,97 @@
+package types_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/crypto/merkle"
+	dbm "github.com/tendermint/tm-db"
+
+	"github.com/cosmos/cosmos-sdk/store/rootmulti"
+	storetypes "github.com/cosmos/cosmos-sdk/store/types"
+	"github.com/cosmos/cosmos-sdk/x/ibc/23-commitment/types"
+)
+
+// commitSubstores commits the given values to a multistore, returning its root
+// and a proof of the key "key" for each substore
+func commitSubstores(t *testing.T, values map[string]string) (types.MerkleRoot, map[string]types.MerkleProof) {
+	store := rootmulti.NewStore(dbm.NewMemDB())
+	keys := make(map[string]*storetypes.KVStoreKey)
+	for name := range values {
+		keys[name] = storetypes.NewKVStoreKey(name)
+		store.MountStoreWithDB(keys[name], storetypes.StoreTypeIAVL, nil)
+	}
+	require.NoError(t, store.LoadVersion(0))
+
+	for name, value := range values {
+		store.GetCommitKVStore(keys[name]).Set([]byte("key"), []byte(value))
+	}
+	cid := store.Commit()
+
+	proofs := make(map[string]types.MerkleProof)
+	for name := range values {
+		res := store.Query(abci.RequestQuery{
+			Path:   fmt.Sprintf("/%s/key", name), // required path to get key/value+proof
+			Data:   []byte("key"),
+			Height: cid.Version,
+			Prove:  true,
+		})
+		require.NotNil(t, res.Proof)
+		proofs[name] = types.MerkleProof{Proof: res.Proof}
+	}
+
+	return types.NewMerkleRoot(cid.Hash), proofs
+}
+
+func TestDiffRoots(t *testing.T) {
+	a, aProofs := commitSubstores(t, map[string]string{"bank": "balance", "ibc": "connection", "staking": "validator"})
+	b, bProofs := commitSubstores(t, map[string]string{"bank": "balance", "ibc": "diverged", "staking": "validator"})
+	c, cProofs := commitSubstores(t, map[string]string{"bank": "other", "ibc": "diverged", "staking": "validator"})
+
+	diff, err := types.DiffRoots(a, b, aProofs, bProofs)
+	require.NoError(t, err)
+	require.Equal(t, []string{"ibc"}, diff)
+
+	diff, err = types.DiffRoots(a, c, aProofs, cProofs)
+	require.NoError(t, err)
+	require.Equal(t, []string{"bank", "ibc"}, diff)
+
+	diff, err = types.DiffRoots(a, a, aProofs, aProofs)
+	require.NoError(t, err)
+	require.Empty(t, diff)
+
+	// a multistore operation whose proof decodes to nil
+	nilMultiStoreProof := types.MerkleProof{Proof: &merkle.Proof{
+		Ops: []merkle.ProofOp{rootmulti.NewMultiStoreProofOp([]byte("bank"), nil).ProofOp()},
+	}}
+
+	testCases := []struct {
+		name             string
+		a, b             types.MerkleRoot
+		aProofs, bProofs map[string]types.MerkleProof
+	}{
+		{"proofs against swapped roots", b, a, aProofs, bProofs},
+		{"missing proof", a, b, map[string]types.MerkleProof{"bank": aProofs["bank"], "ibc": aProofs["ibc"]}, bProofs},
+		{"different substores", a, b,
+			map[string]types.MerkleProof{"bank": aProofs["bank"], "ibc": aProofs["ibc"]},
+			map[string]types.MerkleProof{"bank": bProofs["bank"], "staking": bProofs["staking"]},
+		},
+		{"proof keyed by another substore", a, b,
+			map[string]types.MerkleProof{"bank": aProofs["ibc"], "ibc": aProofs["ibc"], "staking": aProofs["staking"]}, bProofs,
+		},
+		{"empty proof", a, b,
+			map[string]types.MerkleProof{"bank": {}, "ibc": aProofs["ibc"], "staking": aProofs["staking"]}, bProofs,
+		},
+		{"empty multistore proof", a, b,
+			map[string]types.MerkleProof{"bank": nilMultiStoreProof, "ibc": aProofs["ibc"], "staking": aProofs["staking"]}, bProofs,
+		},
+	}
+
+	for _, tc := range testCases {
+		_, err := types.DiffRoots(tc.a, tc.b, tc.aProofs, tc.bProofs)
+		require.Error(t, err, tc.name)
+		require.True(t, types.ErrInvalidProof.Is(err), tc.name)
+	}
+}
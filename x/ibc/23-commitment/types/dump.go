@@ -0,0 +1,80 @@
+package types
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+
+	"github.com/tendermint/tendermint/crypto/merkle"
+
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+const (
+	dumpOpSeparator    = ";"
+	dumpFieldSeparator = ","
+)
+
+// Dump returns a compact, single line text representation of the proof, to be
+// logged and later rebuilt with ParseProofDump. Each proof operation is encoded
+// as "<type>,<hex key>,<base64 data>" and the operations are separated by ";", in
+// the order of the proof.
+func (proof MerkleProof) Dump() string {
+	if proof.Proof == nil {
+		return ""
+	}
+
+	ops := make([]string, len(proof.Proof.Ops))
+	for i, op := range proof.Proof.Ops {
+		ops[i] = strings.Join([]string{
+			op.Type,
+			strings.ToUpper(hex.EncodeToString(op.Key)),
+			base64.StdEncoding.EncodeToString(op.Data),
+		}, dumpFieldSeparator)
+	}
+	return strings.Join(ops, dumpOpSeparator)
+}
+
+// ParseProofDump rebuilds a MerkleProof from the representation returned by
+// MerkleProof.Dump.
+func ParseProofDump(s string) (MerkleProof, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return MerkleProof{}, sdkerrors.Wrap(ErrInvalidProof, "empty proof dump")
+	}
+
+	dumpOps := strings.Split(s, dumpOpSeparator)
+	ops := make([]merkle.ProofOp, len(dumpOps))
+	for i, dumpOp := range dumpOps {
+		fields := strings.Split(dumpOp, dumpFieldSeparator)
+		if len(fields) != 3 {
+			return MerkleProof{}, sdkerrors.Wrapf(
+				ErrInvalidProof, "operation %d: expected 3 fields, got %d", i, len(fields),
+			)
+		}
+
+		if strings.TrimSpace(fields[0]) == "" {
+			return MerkleProof{}, sdkerrors.Wrapf(ErrInvalidProof, "operation %d: empty type", i)
+		}
+
+		key, err := hex.DecodeString(fields[1])
+		if err != nil {
+			return MerkleProof{}, sdkerrors.Wrapf(ErrInvalidProof, "operation %d: invalid key: %s", i, err)
+		}
+
+		data, err := base64.StdEncoding.DecodeString(fields[2])
+		if err != nil {
+			return MerkleProof{}, sdkerrors.Wrapf(ErrInvalidProof, "operation %d: invalid data: %s", i, err)
+		}
+
+		ops[i] = merkle.ProofOp{
+			Type: fields[0],
+			Key:  key,
+			Data: data,
+		}
+	}
+
+	return MerkleProof{
+		Proof: &merkle.Proof{Ops: ops},
+	}, nil
+}
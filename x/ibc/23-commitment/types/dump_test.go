@@ -0,0 +1,59 @@
+package types_test
+
+import (
+	"fmt"
+	"strings"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+
+	"github.com/cosmos/cosmos-sdk/x/ibc/23-commitment/types"
+)
+
+func (suite *MerkleTestSuite) TestParseProofDump() {
+	suite.iavlStore.Set([]byte("MYKEY"), []byte("MYVALUE"))
+	cid := suite.store.Commit()
+
+	res := suite.store.Query(abci.RequestQuery{
+		Path:  fmt.Sprintf("/%s/key", suite.storeKey.Name()), // required path to get key/value+proof
+		Data:  []byte("MYKEY"),
+		Prove: true,
+	})
+	suite.Require().NotNil(res.Proof)
+
+	proof := types.MerkleProof{
+		Proof: res.Proof,
+	}
+	root := types.NewMerkleRoot(cid.Hash)
+	path := types.NewMerklePath([]string{suite.storeKey.Name(), "MYKEY"})
+
+	dump := proof.Dump()
+	suite.Require().False(strings.Contains(dump, "\n"), "dump should be a single line")
+	suite.Require().True(strings.HasPrefix(dump, "iavl:v,4D594B4559,"), dump)
+
+	// dump -> parse -> verify
+	parsed, err := types.ParseProofDump(dump)
+	suite.Require().NoError(err)
+	suite.Require().True(proof.Equal(parsed))
+	suite.Require().NoError(parsed.VerifyMembership(&root, path, []byte("MYVALUE")))
+	suite.Require().Equal(dump, parsed.Dump())
+
+	cases := []struct {
+		name string
+		dump string
+	}{
+		{"empty dump", ""},
+		{"missing fields", "iavl:v,4D594B4559"},
+		{"extra fields", "iavl:v,4D594B4559,AA==,AA=="},
+		{"empty type", ",4D594B4559,AA=="},
+		{"invalid key", "iavl:v,NOTHEX,AA=="},
+		{"invalid data", "iavl:v,4D594B4559,!!!"},
+		{"empty operation", dump + ";"},
+	}
+
+	for _, tc := range cases {
+		_, err := types.ParseProofDump(tc.dump)
+		suite.Require().Error(err, tc.name)
+	}
+
+	suite.Require().Empty(types.MerkleProof{}.Dump())
+}
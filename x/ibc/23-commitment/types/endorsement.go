@@ -0,0 +1,29 @@
+package types
+
+import (
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/cosmos/cosmos-sdk/x/ibc/23-commitment/exported"
+)
+
+// RootEndorsementVerifier defines the interface of the types, such as a light
+// client, that are able to verify that a commitment root is endorsed by a
+// validator set, eg through an aggregated signature over the root.
+type RootEndorsementVerifier interface {
+	VerifyEndorsement(root MerkleRoot, sig []byte) error
+}
+
+// VerifyMembershipEndorsed verifies the endorsement signature over the root with
+// the given endorser and, if it is valid, the membership of the merkle proof
+// against the root, path, and value. The endorsement isn't checked if no endorser
+// is provided, as in VerifyMembership.
+func (proof MerkleProof) VerifyMembershipEndorsed(
+	endorser RootEndorsementVerifier, sig []byte, root MerkleRoot, path exported.Path, value []byte,
+) error {
+	if endorser != nil {
+		if err := endorser.VerifyEndorsement(root, sig); err != nil {
+			return sdkerrors.Wrapf(ErrInvalidRoot, "root %X is not endorsed: %s", root.GetHash(), err)
+		}
+	}
+
+	return proof.VerifyMembership(&root, path, value)
+}
@@ -0,0 +1,84 @@
+package types_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+
+	"github.com/cosmos/cosmos-sdk/x/ibc/23-commitment/types"
+)
+
+var _ types.RootEndorsementVerifier = stubEndorser{}
+
+// stubEndorser accepts the signatures made of the endorsed root prefixed by the
+// signer name, and counts the endorsements it checked
+type stubEndorser struct {
+	signer  []byte
+	checked *int
+}
+
+func (e stubEndorser) VerifyEndorsement(root types.MerkleRoot, sig []byte) error {
+	*e.checked++
+	if !bytes.Equal(sig, append(append([]byte{}, e.signer...), root.GetHash()...)) {
+		return errors.New("invalid signature")
+	}
+	return nil
+}
+
+func (suite *MerkleTestSuite) TestVerifyMembershipEndorsed() {
+	suite.iavlStore.Set([]byte("MYKEY"), []byte("MYVALUE"))
+	cid := suite.store.Commit()
+
+	res := suite.store.Query(abci.RequestQuery{
+		Path:   fmt.Sprintf("/%s/key", suite.storeKey.Name()), // required path to get key/value+proof
+		Data:   []byte("MYKEY"),
+		Height: cid.Version,
+		Prove:  true,
+	})
+	suite.Require().NotNil(res.Proof)
+
+	proof := types.MerkleProof{
+		Proof: res.Proof,
+	}
+	root := types.NewMerkleRoot(cid.Hash)
+	path := types.NewMerklePath([]string{suite.storeKey.Name(), "MYKEY"})
+
+	var checked int
+	endorser := stubEndorser{signer: []byte("validators"), checked: &checked}
+	sig := append([]byte("validators"), cid.Hash...)
+
+	cases := []struct {
+		name       string
+		endorser   types.RootEndorsementVerifier
+		sig        []byte
+		value      []byte
+		expChecked int
+		expPass    bool
+	}{
+		{"endorsed root", endorser, sig, []byte("MYVALUE"), 1, true},
+		{"endorsed root with wrong value", endorser, sig, []byte("WRONGVALUE"), 1, false},
+		{"invalid endorsement", endorser, append([]byte("others"), cid.Hash...), []byte("MYVALUE"), 1, false},
+		{"missing endorsement", endorser, nil, []byte("MYVALUE"), 1, false},
+		{"no endorser", nil, nil, []byte("MYVALUE"), 0, true},
+		{"no endorser with wrong value", nil, sig, []byte("WRONGVALUE"), 0, false},
+	}
+
+	for i, tc := range cases {
+		tc := tc
+		suite.Run(tc.name, func() {
+			checked = 0
+			err := proof.VerifyMembershipEndorsed(tc.endorser, tc.sig, root, path, tc.value)
+
+			if tc.expPass {
+				// nolint: scopelint
+				suite.Require().NoError(err, "test case %d should have passed", i)
+			} else {
+				// nolint: scopelint
+				suite.Require().Error(err, "test case %d should have failed", i)
+			}
+			suite.Require().Equal(tc.expChecked, checked, "test case %d", i)
+		})
+	}
+}
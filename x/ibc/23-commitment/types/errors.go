@@ -9,6 +9,11 @@ const SubModuleName string = "commitment"
 
 // IBC connection sentinel errors
 var (
-	ErrInvalidProof  = sdkerrors.Register(SubModuleName, 2, "invalid proof")
-	ErrInvalidPrefix = sdkerrors.Register(SubModuleName, 3, "invalid prefix")
+	ErrInvalidProof        = sdkerrors.Register(SubModuleName, 2, "invalid proof")
+	ErrInvalidPrefix       = sdkerrors.Register(SubModuleName, 3, "invalid prefix")
+	ErrInvalidAbsenceProof = sdkerrors.Register(SubModuleName, 4, "invalid absence proof")
+	ErrInvalidRoot         = sdkerrors.Register(SubModuleName, 5, "invalid root")
+	ErrRateLimited         = sdkerrors.Register(SubModuleName, 6, "proof verification rate limit exceeded")
+	ErrClientFrozen        = sdkerrors.Register(SubModuleName, 7, "client is frozen")
+	ErrClientExpired       = sdkerrors.Register(SubModuleName, 8, "client is expired")
 )
@@ -0,0 +1,47 @@
+package types
+
+import (
+	"bytes"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+// MerkleRootFromEvent returns the root carried by the attribute of the given
+// key in the events, for chains that expose their app hash through a block event
+// rather than the header. The attribute value must be hex or base64 encoded (see
+// RootMatches). The attribute may be emitted by several events, as long as they
+// all carry the same root.
+func MerkleRootFromEvent(events []abci.Event, attrKey string) (MerkleRoot, error) {
+	if attrKey == "" {
+		return MerkleRoot{}, sdkerrors.Wrap(ErrInvalidRoot, "attribute key cannot be empty")
+	}
+
+	var hash []byte
+	for _, event := range events {
+		for _, attr := range event.Attributes {
+			if string(attr.Key) != attrKey {
+				continue
+			}
+
+			decoded, err := decodeRootHash(string(attr.Value))
+			if err != nil {
+				return MerkleRoot{}, sdkerrors.Wrapf(err, "event %s", event.Type)
+			}
+
+			if hash != nil && !bytes.Equal(hash, decoded) {
+				return MerkleRoot{}, sdkerrors.Wrapf(
+					ErrInvalidRoot, "conflicting roots %X and %X in event attributes %s", hash, decoded, attrKey,
+				)
+			}
+			hash = decoded
+		}
+	}
+
+	if hash == nil {
+		return MerkleRoot{}, sdkerrors.Wrapf(ErrInvalidRoot, "no event attribute %s found", attrKey)
+	}
+
+	return NewMerkleRoot(hash), nil
+}
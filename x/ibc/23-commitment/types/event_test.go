@@ -0,0 +1,68 @@
+package types_test
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/libs/kv"
+
+	"github.com/cosmos/cosmos-sdk/x/ibc/23-commitment/types"
+)
+
+func TestMerkleRootFromEvent(t *testing.T) {
+	hash := []byte("0123456789abcdef0123456789abcdef")
+	otherHash := []byte("fedcba9876543210fedcba9876543210")
+
+	event := func(eventType string, attrs ...kv.Pair) abci.Event {
+		return abci.Event{Type: eventType, Attributes: attrs}
+	}
+	attr := func(key, value string) kv.Pair {
+		return kv.Pair{Key: []byte(key), Value: []byte(value)}
+	}
+
+	hexRoot := hex.EncodeToString(hash)
+	base64Root := base64.StdEncoding.EncodeToString(hash)
+
+	cases := []struct {
+		name    string
+		events  []abci.Event
+		attrKey string
+		expPass bool
+	}{
+		{"hex encoded root", []abci.Event{
+			event("transfer", attr("amount", "10stake")),
+			event("commit", attr("height", "10"), attr("app_hash", hexRoot)),
+		}, "app_hash", true},
+		{"base64 encoded root", []abci.Event{event("commit", attr("app_hash", base64Root))}, "app_hash", true},
+		{"same root in several events", []abci.Event{
+			event("commit", attr("app_hash", hexRoot)),
+			event("state_root", attr("app_hash", base64Root)),
+		}, "app_hash", true},
+		{"conflicting roots", []abci.Event{
+			event("commit", attr("app_hash", hexRoot)),
+			event("state_root", attr("app_hash", hex.EncodeToString(otherHash))),
+		}, "app_hash", false},
+		{"undecodable root", []abci.Event{event("commit", attr("app_hash", "not a root!"))}, "app_hash", false},
+		{"empty root", []abci.Event{event("commit", attr("app_hash", ""))}, "app_hash", false},
+		{"attribute not found", []abci.Event{event("commit", attr("height", "10"))}, "app_hash", false},
+		{"no events", nil, "app_hash", false},
+		{"empty attribute key", []abci.Event{event("commit", attr("", hexRoot))}, "", false},
+	}
+
+	for _, tc := range cases {
+		root, err := types.MerkleRootFromEvent(tc.events, tc.attrKey)
+
+		if tc.expPass {
+			require.NoError(t, err, tc.name)
+			require.Equal(t, types.NewMerkleRoot(hash), root, tc.name)
+		} else {
+			require.Error(t, err, tc.name)
+			require.True(t, types.ErrInvalidRoot.Is(err), tc.name)
+			require.True(t, root.IsEmpty(), tc.name)
+		}
+	}
+}
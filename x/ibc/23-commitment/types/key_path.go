@@ -3,6 +3,7 @@ package types
 import (
 	fmt "fmt"
 	"net/url"
+	"strings"
 )
 
 // AppendKey appends a new key to a KeyPath
@@ -26,3 +27,43 @@ func (pth *KeyPath) String() string {
 	}
 	return res
 }
+
+// StringRFC3986 returns the string representation of the KeyPath with URL keys
+// percent-encoded as defined in RFC 3986: every byte outside the unreserved set
+// is escaped using uppercase hex digits. Unlike url.PathEscape, sub-delimiters
+// such as '+', ':' or '@' are escaped too, which makes the output independent of
+// the escaping rules of a particular implementation.
+func (pth *KeyPath) StringRFC3986() string {
+	res := ""
+	for _, key := range pth.Keys {
+		switch key.enc {
+		case URL:
+			res += "/" + escapeRFC3986(key.name)
+		case HEX:
+			res += "/x:" + fmt.Sprintf("%X", key.name)
+		default:
+			panic("unexpected key encoding type")
+		}
+	}
+	return res
+}
+
+// escapeRFC3986 percent-encodes all the bytes that are not part of the RFC 3986
+// unreserved character set.
+func escapeRFC3986(bz []byte) string {
+	const upperhex = "0123456789ABCDEF"
+
+	var sb strings.Builder
+	for _, c := range bz {
+		switch {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9',
+			c == '-', c == '.', c == '_', c == '~':
+			sb.WriteByte(c)
+		default:
+			sb.WriteByte('%')
+			sb.WriteByte(upperhex[c>>4])
+			sb.WriteByte(upperhex[c&15])
+		}
+	}
+	return sb.String()
+}
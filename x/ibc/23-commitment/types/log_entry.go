@@ -0,0 +1,28 @@
+package types
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/ibc/23-commitment/exported"
+)
+
+// LogStoreKey defines the name of the store that commits an append-only log
+const LogStoreKey = "log"
+
+// LogEntryPath returns the commitment path of the log entry at the given index:
+// log/{index}, where the index is encoded as fixed-width big-endian bytes so
+// that the lexical order of the keys matches the order of the entries.
+func LogEntryPath(index uint64) MerklePath {
+	keyPath := KeyPath{}
+	keyPath = keyPath.AppendKey([]byte(LogStoreKey), URL)
+	keyPath = keyPath.AppendKey(sdk.Uint64ToBigEndian(index), HEX)
+
+	return MerklePath{
+		KeyPath: keyPath,
+	}
+}
+
+// VerifyLogEntry verifies that the append-only log committed to the root stores
+// the given value at the given index.
+func VerifyLogEntry(proof MerkleProof, root exported.Root, index uint64, value []byte) error {
+	return proof.VerifyMembership(root, LogEntryPath(index), value)
+}
@@ -0,0 +1,59 @@
+package types_test
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	dbm "github.com/tendermint/tm-db"
+
+	"github.com/cosmos/cosmos-sdk/store/iavl"
+	"github.com/cosmos/cosmos-sdk/store/rootmulti"
+	storetypes "github.com/cosmos/cosmos-sdk/store/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/ibc/23-commitment/types"
+)
+
+func TestVerifyLogEntry(t *testing.T) {
+	db := dbm.NewMemDB()
+	store := rootmulti.NewStore(db)
+	storeKey := storetypes.NewKVStoreKey(types.LogStoreKey)
+	store.MountStoreWithDB(storeKey, storetypes.StoreTypeIAVL, nil)
+	require.NoError(t, store.LoadVersion(0))
+
+	// NOTE: the IAVL store cannot build a proof for the all 0xFF key, as the end of
+	// its proof range overflows, so MaxUint64 is only checked on the path format
+	indices := []uint64{0, 1, 255, 256, math.MaxUint64 - 1}
+	logStore := store.GetCommitStore(storeKey).(*iavl.Store)
+	for _, index := range indices {
+		logStore.Set(sdk.Uint64ToBigEndian(index), []byte(fmt.Sprintf("entry %d", index)))
+	}
+	cid := store.Commit()
+	root := types.NewMerkleRoot(cid.Hash)
+
+	query := func(index uint64) types.MerkleProof {
+		res := store.Query(abci.RequestQuery{
+			Path:   fmt.Sprintf("/%s/key", storeKey.Name()), // required path to get key/value+proof
+			Data:   sdk.Uint64ToBigEndian(index),
+			Height: cid.Version,
+			Prove:  true,
+		})
+		require.NotNil(t, res.Proof)
+		return types.MerkleProof{Proof: res.Proof}
+	}
+
+	for _, index := range indices {
+		proof := query(index)
+		value := []byte(fmt.Sprintf("entry %d", index))
+
+		require.NoError(t, types.VerifyLogEntry(proof, &root, index, value), "index %d", index)
+		require.Error(t, types.VerifyLogEntry(proof, &root, index, []byte("wrong entry")), "index %d", index)
+		require.Error(t, types.VerifyLogEntry(proof, &root, index^1, value), "index %d", index)
+	}
+
+	require.Equal(t, "/log/x:0000000000000001", types.LogEntryPath(1).String())
+	require.Equal(t, "/log/x:0000000000000100", types.LogEntryPath(256).String())
+	require.Equal(t, "/log/x:FFFFFFFFFFFFFFFF", types.LogEntryPath(math.MaxUint64).String())
+}
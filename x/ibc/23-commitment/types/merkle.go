@@ -1,14 +1,23 @@
 package types
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding"
+	"encoding/binary"
 	"errors"
+	"fmt"
 	"net/url"
+	"strings"
 
 	"github.com/cosmos/cosmos-sdk/store/rootmulti"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
 	"github.com/cosmos/cosmos-sdk/x/ibc/23-commitment/exported"
 	host "github.com/cosmos/cosmos-sdk/x/ibc/24-host"
 
+	"github.com/tendermint/iavl"
 	"github.com/tendermint/tendermint/crypto/merkle"
+	"github.com/tendermint/tendermint/libs/log"
 )
 
 // ICS 023 Merkle Types Implementation
@@ -17,7 +26,11 @@ import (
 
 // Merkle proof implementation of the Proof interface
 // Applied on SDK-based IBC implementation
-var _ exported.Root = (*MerkleRoot)(nil)
+var (
+	_ exported.Root              = (*MerkleRoot)(nil)
+	_ encoding.BinaryMarshaler   = MerkleRoot{}
+	_ encoding.BinaryUnmarshaler = (*MerkleRoot)(nil)
+)
 
 // NewMerkleRoot constructs a new MerkleRoot
 func NewMerkleRoot(hash []byte) MerkleRoot {
@@ -41,6 +54,53 @@ func (mr MerkleRoot) IsEmpty() bool {
 	return len(mr.GetHash()) == 0
 }
 
+// IsZero returns true if the root hash is not empty and all its bytes are zero,
+// which is the case of roots taken from an uninitialized consensus state.
+func (mr MerkleRoot) IsZero() bool {
+	return isZeroHash(mr.GetHash())
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler. It encodes the root as its
+// commitment type byte followed by the root hash.
+func (mr MerkleRoot) MarshalBinary() ([]byte, error) {
+	bz := make([]byte, 0, 1+len(mr.Hash))
+	bz = append(bz, byte(mr.GetCommitmentType()))
+	return append(bz, mr.Hash...), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler. It decodes a root
+// encoded by MarshalBinary.
+func (mr *MerkleRoot) UnmarshalBinary(bz []byte) error {
+	if len(bz) == 0 {
+		return sdkerrors.Wrap(ErrInvalidRoot, "missing commitment type")
+	}
+
+	if commitmentType := exported.Type(bz[0]); commitmentType != exported.Merkle {
+		return sdkerrors.Wrapf(ErrInvalidRoot, "invalid commitment type, expected %s, got %s", exported.Merkle, commitmentType)
+	}
+
+	*mr = MerkleRoot{}
+	if len(bz) > 1 {
+		mr.Hash = append([]byte(nil), bz[1:]...)
+	}
+	return nil
+}
+
+// isZeroHash returns true if the hash is not empty and contains only zero bytes.
+func isZeroHash(hash []byte) bool {
+	if len(hash) == 0 {
+		return false
+	}
+
+	for _, b := range hash {
+		if b != 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
 var _ exported.Prefix = (*MerklePrefix)(nil)
 
 // NewMerklePrefix constructs new MerklePrefix instance
@@ -50,6 +110,13 @@ func NewMerklePrefix(keyPrefix []byte) MerklePrefix {
 	}
 }
 
+// PrefixForModule returns the prefix of the state owned by the given module. The
+// modules' substores are committed to the multistore under the name of their
+// store key, which is the module name, so the prefix is the module name itself.
+func PrefixForModule(moduleName string) MerklePrefix {
+	return NewMerklePrefix([]byte(moduleName))
+}
+
 // GetCommitmentType implements Prefix interface
 func (MerklePrefix) GetCommitmentType() exported.Type {
 	return exported.Merkle
@@ -65,6 +132,16 @@ func (mp MerklePrefix) IsEmpty() bool {
 	return len(mp.Bytes()) == 0
 }
 
+// Append returns a new prefix whose bytes are the bytes of the prefix followed by
+// the bytes of the other prefix, ie a.Append(b) is the prefix "ab". Neither of
+// the prefixes is modified.
+func (mp MerklePrefix) Append(other MerklePrefix) MerklePrefix {
+	keyPrefix := make([]byte, 0, len(mp.KeyPrefix)+len(other.KeyPrefix))
+	keyPrefix = append(keyPrefix, mp.KeyPrefix...)
+	keyPrefix = append(keyPrefix, other.KeyPrefix...)
+	return NewMerklePrefix(keyPrefix)
+}
+
 var _ exported.Path = (*MerklePath)(nil)
 
 // NewMerklePath creates a new MerklePath instance
@@ -89,6 +166,13 @@ func (mp MerklePath) String() string {
 	return mp.KeyPath.String()
 }
 
+// StringRFC3986 returns the path with each key percent-encoded as defined in
+// RFC 3986 (see KeyPath.StringRFC3986). Proof verification decodes every key
+// before comparing it, so a proof verifies against either string form.
+func (mp MerklePath) StringRFC3986() string {
+	return mp.KeyPath.StringRFC3986()
+}
+
 // Pretty returns the unescaped path of the URL string.
 func (mp MerklePath) Pretty() string {
 	path, err := url.PathUnescape(mp.KeyPath.String())
@@ -103,6 +187,109 @@ func (mp MerklePath) IsEmpty() bool {
 	return len(mp.KeyPath.Keys) == 0
 }
 
+// Segments returns the decoded keys of the path, in order.
+func (mp MerklePath) Segments() []string {
+	segments := make([]string, len(mp.KeyPath.Keys))
+	for i, key := range mp.KeyPath.Keys {
+		segments[i] = string(key.name)
+	}
+	return segments
+}
+
+// Hash returns the SHA256 hash of the decoded keys of the path, each prefixed by
+// its uvarint encoded length. Paths with the same keys hash equally regardless of
+// how their keys are encoded, so the hash can be used as a fixed-size map key.
+func (mp MerklePath) Hash() [32]byte {
+	hasher := sha256.New()
+	for _, key := range mp.KeyPath.Keys {
+		hasher.Write(lengthPrefix(key.name))
+	}
+
+	var hash [32]byte
+	copy(hash[:], hasher.Sum(nil))
+	return hash
+}
+
+// Matches returns true if the path matches the given pattern. The path and the
+// pattern must have the same number of keys, and each key is compared segment by
+// segment, using '/' as the separator. A '*' pattern segment matches exactly one
+// segment, so the key "ports/transfer/channels/channel-0" matches the pattern key
+// "ports/*/channels/*" but not "ports/*".
+func (mp MerklePath) Matches(pattern MerklePath) bool {
+	if len(mp.KeyPath.Keys) != len(pattern.KeyPath.Keys) {
+		return false
+	}
+
+	for i, key := range mp.KeyPath.Keys {
+		segments := strings.Split(string(key.name), "/")
+		patternSegments := strings.Split(string(pattern.KeyPath.Keys[i].name), "/")
+		if len(segments) != len(patternSegments) {
+			return false
+		}
+
+		for j, segment := range segments {
+			if patternSegments[j] != "*" && patternSegments[j] != segment {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// SuffixEqual returns true if the last suffixLen segments of the path equal the
+// ones of the other path, regardless of the segments before them (eg: the store
+// or client prefix). As in Matches, segments are the keys of the path split by
+// '/', so the keys "clients/clientA/clientState" and "clients/clientB/clientState"
+// have equal suffixes of up to 1 segment. It returns false if suffixLen is not
+// positive or if a path has less segments.
+func (mp MerklePath) SuffixEqual(other MerklePath, suffixLen int) bool {
+	if suffixLen <= 0 {
+		return false
+	}
+
+	segments, otherSegments := mp.splitSegments(), other.splitSegments()
+	if len(segments) < suffixLen || len(otherSegments) < suffixLen {
+		return false
+	}
+
+	segments = segments[len(segments)-suffixLen:]
+	otherSegments = otherSegments[len(otherSegments)-suffixLen:]
+	for i, segment := range segments {
+		if segment != otherSegments[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// splitSegments returns the keys of the path split by '/', in order.
+func (mp MerklePath) splitSegments() []string {
+	var segments []string
+	for _, key := range mp.KeyPath.Keys {
+		segments = append(segments, strings.Split(string(key.name), "/")...)
+	}
+	return segments
+}
+
+// LastN returns a path containing the last n keys of the path, for display
+// purposes. The whole path is returned if n is greater than its number of keys,
+// and an empty path if n is not positive.
+func (mp MerklePath) LastN(n int) MerklePath {
+	keys := mp.KeyPath.Keys
+	switch {
+	case n <= 0:
+		return MerklePath{}
+	case n < len(keys):
+		keys = keys[len(keys)-n:]
+	}
+
+	return MerklePath{
+		KeyPath: KeyPath{Keys: append([]*Key{}, keys...)},
+	}
+}
+
 // ApplyPrefix constructs a new commitment path from the arguments. It interprets
 // the path argument in the context of the prefix argument.
 //
@@ -120,6 +307,32 @@ func ApplyPrefix(prefix exported.Prefix, path string) (MerklePath, error) {
 	return NewMerklePath([]string{string(prefix.Bytes()), path}), nil
 }
 
+// ApplyPrefixLengthPrefixed constructs a new commitment path from the arguments
+// in the same way as ApplyPrefix, but prepends the uvarint encoded length to each
+// of the segments. This prevents a prefix that is a strict prefix of another
+// namespace (eg: "acc" and "account") from committing to an ambiguous key.
+func ApplyPrefixLengthPrefixed(prefix exported.Prefix, path string) (MerklePath, error) {
+	err := host.PathValidator(path)
+	if err != nil {
+		return MerklePath{}, err
+	}
+
+	if prefix == nil || prefix.IsEmpty() {
+		return MerklePath{}, errors.New("prefix can't be empty")
+	}
+	return NewMerklePath([]string{
+		string(lengthPrefix(prefix.Bytes())),
+		string(lengthPrefix([]byte(path))),
+	}), nil
+}
+
+// lengthPrefix returns the given bytes prefixed by their uvarint encoded length
+func lengthPrefix(bz []byte) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(buf[:], uint64(len(bz)))
+	return append(buf[:n], bz...)
+}
+
 var _ exported.Proof = (*MerkleProof)(nil)
 
 // GetCommitmentType implements ProofI
@@ -133,18 +346,330 @@ func (proof MerkleProof) VerifyMembership(root exported.Root, path exported.Path
 		return errors.New("empty params or proof")
 	}
 
+	if isZeroHash(root.GetHash()) {
+		return errors.New("root hash cannot be zero")
+	}
+
 	runtime := rootmulti.DefaultProofRuntime()
 	return runtime.VerifyValue(proof.Proof, root.GetHash(), path.String(), value)
 }
 
+// VerifyMembershipRaw verifies the membership of a merkle proof against the
+// given root hash. See VerifyMembership.
+func (proof MerkleProof) VerifyMembershipRaw(rootHash []byte, path exported.Path, value []byte) error {
+	root := NewMerkleRoot(rootHash)
+	return proof.VerifyMembership(&root, path, value)
+}
+
+// VerifyMembershipCommitment verifies the membership of a merkle proof against
+// the given root and path, where the value committed to the store is the given
+// commitment (eg a hash of a confidential value) rather than the value itself.
+//
+// NOTE: the commitment is verified as is. The caller is responsible for the
+// scheme used to derive it from the value.
+func (proof MerkleProof) VerifyMembershipCommitment(root exported.Root, path exported.Path, commitment []byte) error {
+	return proof.VerifyMembership(root, path, commitment)
+}
+
+// VerifyMembershipSalted verifies the membership of a merkle proof against the
+// given root and path, where the value committed to the store is the given value
+// prefixed with the salt (ie salt || value), as used by counterparties that
+// domain separate their leaf values.
+func (proof MerkleProof) VerifyMembershipSalted(root exported.Root, path exported.Path, value, salt []byte) error {
+	if len(value) == 0 {
+		return errors.New("empty params or proof")
+	}
+
+	salted := make([]byte, 0, len(salt)+len(value))
+	salted = append(salted, salt...)
+	salted = append(salted, value...)
+	return proof.VerifyMembership(root, path, salted)
+}
+
+// VerifyMembershipHashedPath verifies the membership of a merkle proof against
+// the given root and value, where the key committed to the store is the SHA-256
+// hash of the path instead of the path itself, as used by counterparties that
+// keep their store keys private. The first key of the path names the store and
+// is kept as is, the remaining keys are joined by "/" and hashed.
+func (proof MerkleProof) VerifyMembershipHashedPath(root exported.Root, path exported.Path, value []byte) error {
+	mp, ok := path.(MerklePath)
+	if !ok {
+		return sdkerrors.Wrapf(ErrInvalidProof, "expected %T, got %T", MerklePath{}, path)
+	}
+
+	if len(mp.KeyPath.Keys) < 2 {
+		return sdkerrors.Wrap(ErrInvalidProof, "hashed path must contain a store key and at least one key")
+	}
+
+	storeKey := mp.KeyPath.Keys[0]
+	segments := mp.Segments()[1:]
+	storePath := sha256.Sum256([]byte(strings.Join(segments, "/")))
+
+	keyPath := KeyPath{}.AppendKey(storeKey.name, storeKey.enc).AppendKey(storePath[:], HEX)
+	return proof.VerifyMembership(root, MerklePath{KeyPath: keyPath}, value)
+}
+
+// VerifyMembershipEitherPrefix verifies the membership of a merkle proof against
+// the given root and value, for the path under any of the given prefixes (eg: the
+// prefixes before and after a key migration). Each prefix is applied to the raw
+// path, as its first key, and the proof is verified against the resulting paths
+// in order until one succeeds.
+func (proof MerkleProof) VerifyMembershipEitherPrefix(root exported.Root, prefixes []MerklePrefix, rawPath MerklePath, value []byte) error {
+	if len(prefixes) == 0 || rawPath.IsEmpty() {
+		return errors.New("empty params or proof")
+	}
+
+	var errs []string
+	for _, prefix := range prefixes {
+		if prefix.IsEmpty() {
+			return errors.New("prefix can't be empty")
+		}
+
+		keyPath := KeyPath{}.AppendKey(prefix.Bytes(), URL)
+		keyPath.Keys = append(keyPath.Keys, rawPath.KeyPath.Keys...)
+
+		err := proof.VerifyMembership(root, MerklePath{KeyPath: keyPath}, value)
+		if err == nil {
+			return nil
+		}
+		errs = append(errs, fmt.Sprintf("prefix %s: %s", prefix.Bytes(), err))
+	}
+
+	return sdkerrors.Wrapf(ErrInvalidProof, "proof failed under all prefixes: %s", strings.Join(errs, "; "))
+}
+
+// VerifyTombstone verifies that the key of the given path is logically deleted,
+// ie that the proof commits the key to the given tombstone sentinel value under
+// the root. A proof of absence of the key is rejected, as a tombstoned key still
+// exists in the store, so a logical deletion can be told apart from a key that
+// was never set or was physically deleted.
+func (proof MerkleProof) VerifyTombstone(root exported.Root, path exported.Path, tombstone []byte) error {
+	if proof.IsEmpty() || len(tombstone) == 0 {
+		return errors.New("empty params or proof")
+	}
+
+	if proof.Proof.Ops[0].Type == iavl.ProofOpIAVLAbsence {
+		return sdkerrors.Wrapf(ErrInvalidProof, "key %s is absent, not tombstoned", path)
+	}
+
+	if err := proof.VerifyMembership(root, path, tombstone); err != nil {
+		return sdkerrors.Wrapf(ErrInvalidProof, "key %s is not tombstoned: %s", path, err)
+	}
+	return nil
+}
+
+// VerifyMembershipWithLogger verifies the membership of a merkle proof against the
+// given root, path, and value. For each proof operation it logs, at debug level,
+// the operation index, the subpath key it consumed and the subroot it computed.
+// A nil logger falls back to VerifyMembership.
+func (proof MerkleProof) VerifyMembershipWithLogger(logger log.Logger, root exported.Root, path exported.Path, value []byte) error {
+	if logger == nil {
+		return proof.VerifyMembership(root, path, value)
+	}
+
+	if proof.IsEmpty() || root == nil || root.IsEmpty() || path == nil || path.IsEmpty() || len(value) == 0 {
+		return errors.New("empty params or proof")
+	}
+
+	if isZeroHash(root.GetHash()) {
+		return errors.New("root hash cannot be zero")
+	}
+
+	runtime := rootmulti.DefaultProofRuntime()
+	poz, err := runtime.DecodeProof(proof.Proof)
+	if err != nil {
+		return sdkerrors.Wrap(ErrInvalidProof, err.Error())
+	}
+
+	return verifyOperators(poz, root.GetHash(), path.String(), [][]byte{value}, func(i int, key, subroot []byte) {
+		logger.Debug(
+			"computed proof subroot",
+			"index", i, "subpath", string(key), "subroot", fmt.Sprintf("%X", subroot),
+		)
+	})
+}
+
+// VerifyMembershipAnySpecOrder verifies the membership of a merkle proof whose
+// operations may have been ordered from the root down instead of from the lowest
+// subtree up (eg: [multistore, iavl:v] instead of [iavl:v, multistore]). It
+// verifies the proof as-is and, on failure, retries with the operations reversed.
+// The order that succeeded is logged to the logger, if provided.
+func (proof MerkleProof) VerifyMembershipAnySpecOrder(logger log.Logger, root exported.Root, path exported.Path, value []byte) error {
+	if logger == nil {
+		logger = log.NewNopLogger()
+	}
+
+	err := proof.VerifyMembership(root, path, value)
+	if err == nil {
+		logger.Debug("verified proof operations", "order", "as-is")
+		return nil
+	}
+
+	if proof.IsEmpty() || len(proof.Proof.Ops) < 2 {
+		return err
+	}
+
+	ops := make([]merkle.ProofOp, len(proof.Proof.Ops))
+	for i, op := range proof.Proof.Ops {
+		ops[len(ops)-1-i] = op
+	}
+
+	reversed := MerkleProof{Proof: &merkle.Proof{Ops: ops}}
+	if reversedErr := reversed.VerifyMembership(root, path, value); reversedErr != nil {
+		return sdkerrors.Wrapf(err, "proof with reversed operations also failed: %s", reversedErr)
+	}
+
+	logger.Debug("verified proof operations", "order", "reversed")
+	return nil
+}
+
+// verifyOperators runs the chained proof operators from the lowest subtree up to
+// the root, consuming the keys of the keypath from last to first. It mirrors
+// merkle.ProofOperators.Verify but calls the optional hook with the subroot
+// computed by each operation.
+func verifyOperators(
+	poz merkle.ProofOperators, root []byte, keypath string, args [][]byte,
+	hook func(i int, key, subroot []byte),
+) error {
+	keys, err := merkle.KeyPathToKeys(keypath)
+	if err != nil {
+		return sdkerrors.Wrap(ErrInvalidProof, err.Error())
+	}
+
+	for i, op := range poz {
+		key := op.GetKey()
+		if len(key) != 0 {
+			if len(keys) == 0 {
+				return sdkerrors.Wrapf(ErrInvalidProof, "key path has insufficient # of parts: expected no more keys but got %s", key)
+			}
+
+			lastKey := keys[len(keys)-1]
+			if !bytes.Equal(lastKey, key) {
+				return sdkerrors.Wrapf(ErrInvalidProof, "key mismatch on operation #%d: expected %s but got %s", i, lastKey, key)
+			}
+			keys = keys[:len(keys)-1]
+		}
+
+		args, err = op.Run(args)
+		if err != nil {
+			return sdkerrors.Wrapf(ErrInvalidProof, "operation #%d: %s", i, err)
+		}
+
+		if hook != nil {
+			hook(i, key, args[0])
+		}
+	}
+
+	if !bytes.Equal(root, args[0]) {
+		return sdkerrors.Wrapf(ErrInvalidProof, "calculated root hash is invalid: expected %X but got %X", root, args[0])
+	}
+
+	if len(keys) != 0 {
+		return sdkerrors.Wrap(ErrInvalidProof, "keypath not consumed all")
+	}
+
+	return nil
+}
+
 // VerifyNonMembership verifies the absence of a merkle proof against the given root and path.
 func (proof MerkleProof) VerifyNonMembership(root exported.Root, path exported.Path) error {
 	if proof.IsEmpty() || root == nil || root.IsEmpty() || path == nil || path.IsEmpty() {
 		return errors.New("empty params or proof")
 	}
 
+	if isZeroHash(root.GetHash()) {
+		return errors.New("root hash cannot be zero")
+	}
+
+	// the IAVL absence operation checks that its leaves bracket the key; the error
+	// is only wrapped to report it as an invalid absence proof
 	runtime := rootmulti.DefaultProofRuntime()
-	return runtime.VerifyAbsence(proof.Proof, root.GetHash(), path.String())
+	if err := runtime.VerifyAbsence(proof.Proof, root.GetHash(), path.String()); err != nil {
+		return sdkerrors.Wrap(ErrInvalidAbsenceProof, err.Error())
+	}
+
+	return nil
+}
+
+// VerifyDeletionThenRecreation verifies that the key at the given path didn't
+// exist at the root of deletion, and that it was later recreated committing the
+// new value at the root of recreation.
+func VerifyDeletionThenRecreation(
+	delProof MerkleProof, delRoot exported.Root,
+	recreateProof MerkleProof, recreateRoot exported.Root,
+	path exported.Path, newValue []byte,
+) error {
+	if err := delProof.VerifyNonMembership(delRoot, path); err != nil {
+		return sdkerrors.Wrap(err, "failed to verify deletion")
+	}
+
+	if err := recreateProof.VerifyMembership(recreateRoot, path, newValue); err != nil {
+		return sdkerrors.Wrap(err, "failed to verify recreation")
+	}
+
+	return nil
+}
+
+// VerifyTransition verifies a state transition at the given path: the key
+// committed the before value at the before root, and the after value at the
+// after root.
+func VerifyTransition(
+	beforeProof MerkleProof, beforeRoot exported.Root,
+	afterProof MerkleProof, afterRoot exported.Root,
+	path exported.Path, before, after []byte,
+) error {
+	if err := beforeProof.VerifyMembership(beforeRoot, path, before); err != nil {
+		return sdkerrors.Wrap(err, "failed to verify value before transition")
+	}
+
+	if err := afterProof.VerifyMembership(afterRoot, path, after); err != nil {
+		return sdkerrors.Wrap(err, "failed to verify value after transition")
+	}
+
+	return nil
+}
+
+// SemanticEqual returns true if both proofs prove the same facts: each proof
+// operation has the same type and key, computes the same subroot and commits to
+// the same leaves. Differences in how the operations were serialized, such as
+// the order of the store infos of a multistore operation, are ignored.
+func (proof MerkleProof) SemanticEqual(other MerkleProof) bool {
+	if proof.IsEmpty() || other.IsEmpty() {
+		return proof.IsEmpty() && other.IsEmpty()
+	}
+
+	if len(proof.Proof.Ops) != len(other.Proof.Ops) {
+		return false
+	}
+
+	runtime := rootmulti.DefaultProofRuntime()
+	poz, err := runtime.DecodeProof(proof.Proof)
+	if err != nil {
+		return false
+	}
+
+	otherPoz, err := runtime.DecodeProof(other.Proof)
+	if err != nil {
+		return false
+	}
+
+	for i := range poz {
+		facts, err := getOperatorFacts(poz[i])
+		if err != nil {
+			return false
+		}
+
+		otherFacts, err := getOperatorFacts(otherPoz[i])
+		if err != nil {
+			return false
+		}
+
+		if !facts.equal(otherFacts) {
+			return false
+		}
+	}
+
+	return true
 }
 
 // IsEmpty returns true if the root is empty
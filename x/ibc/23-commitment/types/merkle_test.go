@@ -1,14 +1,27 @@
 package types_test
 
 import (
+	"bytes"
+	"crypto/sha256"
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/cosmos/cosmos-sdk/store/iavl"
+	"github.com/cosmos/cosmos-sdk/store/rootmulti"
+	storetypes "github.com/cosmos/cosmos-sdk/store/types"
+	"github.com/cosmos/cosmos-sdk/x/ibc/23-commitment/exported"
 	"github.com/cosmos/cosmos-sdk/x/ibc/23-commitment/types"
+	host "github.com/cosmos/cosmos-sdk/x/ibc/24-host"
 
+	iavltree "github.com/tendermint/iavl"
 	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/crypto/merkle"
+	"github.com/tendermint/tendermint/crypto/tmhash"
+	"github.com/tendermint/tendermint/libs/log"
+	dbm "github.com/tendermint/tm-db"
 )
 
 func (suite *MerkleTestSuite) TestVerifyMembership() {
@@ -45,6 +58,7 @@ func (suite *MerkleTestSuite) TestVerifyMembership() {
 		{"wrong storekey", cid.Hash, []string{"otherStoreKey", "MYKEY"}, []byte("MYVALUE"), false},              // invalid proof with wrong store prefix
 		{"wrong root", []byte("WRONGROOT"), []string{suite.storeKey.Name(), "MYKEY"}, []byte("MYVALUE"), false}, // invalid proof with wrong root
 		{"nil root", []byte(nil), []string{suite.storeKey.Name(), "MYKEY"}, []byte("MYVALUE"), false},           // invalid proof with nil root
+		{"zero root", make([]byte, 32), []string{suite.storeKey.Name(), "MYKEY"}, []byte("MYVALUE"), false},     // invalid proof with zero root
 	}
 
 	for i, tc := range cases {
@@ -62,11 +76,236 @@ func (suite *MerkleTestSuite) TestVerifyMembership() {
 				// nolint: scopelint
 				suite.Require().Error(err, "test case %d should have failed", i)
 			}
+
+			// verifying against the raw root hash has the same outcome
+			rawErr := proof.VerifyMembershipRaw(tc.root, path, tc.value)
+			if err == nil {
+				suite.Require().NoError(rawErr, "test case %d raw root verification should have passed", i)
+			} else {
+				suite.Require().EqualError(rawErr, err.Error(), "test case %d raw root verification should have failed", i)
+			}
 		})
 	}
 
 }
 
+func (suite *MerkleTestSuite) TestVerifyMembershipWithLogger() {
+	suite.iavlStore.Set([]byte("MYKEY"), []byte("MYVALUE"))
+	cid := suite.store.Commit()
+
+	res := suite.store.Query(abci.RequestQuery{
+		Path:  fmt.Sprintf("/%s/key", suite.storeKey.Name()), // required path to get key/value+proof
+		Data:  []byte("MYKEY"),
+		Prove: true,
+	})
+	require.NotNil(suite.T(), res.Proof)
+
+	proof := types.MerkleProof{
+		Proof: res.Proof,
+	}
+	root := types.NewMerkleRoot(cid.Hash)
+	path := types.NewMerklePath([]string{suite.storeKey.Name(), "MYKEY"})
+
+	var buf bytes.Buffer
+	logger := log.NewTMLogger(log.NewSyncWriter(&buf))
+
+	err := proof.VerifyMembershipWithLogger(logger, &root, path, []byte("MYVALUE"))
+	suite.Require().NoError(err)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	suite.Require().Len(lines, len(res.Proof.Ops), "expected one log line per subtree")
+	suite.Require().Contains(lines[0], "subpath=MYKEY")
+	suite.Require().Contains(lines[1], "subpath="+suite.storeKey.Name())
+	suite.Require().Contains(lines[1], fmt.Sprintf("subroot=%X", cid.Hash))
+
+	// nil logger behaves as VerifyMembership
+	suite.Require().NoError(proof.VerifyMembershipWithLogger(nil, &root, path, []byte("MYVALUE")))
+	suite.Require().Error(proof.VerifyMembershipWithLogger(nil, &root, path, []byte("WRONGVALUE")))
+	suite.Require().Error(proof.VerifyMembershipWithLogger(logger, &root, path, []byte("WRONGVALUE")))
+}
+
+func (suite *MerkleTestSuite) TestVerifyMembershipCommitment() {
+	value := []byte("MYCONFIDENTIALVALUE")
+	commitment := tmhash.Sum(value)
+
+	suite.iavlStore.Set([]byte("MYKEY"), commitment)
+	cid := suite.store.Commit()
+
+	res := suite.store.Query(abci.RequestQuery{
+		Path:  fmt.Sprintf("/%s/key", suite.storeKey.Name()), // required path to get key/value+proof
+		Data:  []byte("MYKEY"),
+		Prove: true,
+	})
+	require.NotNil(suite.T(), res.Proof)
+
+	proof := types.MerkleProof{
+		Proof: res.Proof,
+	}
+	root := types.NewMerkleRoot(cid.Hash)
+	path := types.NewMerklePath([]string{suite.storeKey.Name(), "MYKEY"})
+
+	suite.Require().NoError(proof.VerifyMembershipCommitment(&root, path, commitment))
+	suite.Require().Error(proof.VerifyMembershipCommitment(&root, path, value), "value verified without its commitment")
+	suite.Require().Error(proof.VerifyMembershipCommitment(&root, path, tmhash.Sum([]byte("MYOTHERVALUE"))))
+	suite.Require().Error(proof.VerifyMembershipCommitment(&root, path, nil))
+}
+
+func (suite *MerkleTestSuite) TestVerifyMembershipSalted() {
+	salt := []byte("ibc-leaf:")
+
+	suite.iavlStore.Set([]byte("MYKEY"), []byte("ibc-leaf:MYVALUE"))
+	cid := suite.store.Commit()
+
+	res := suite.store.Query(abci.RequestQuery{
+		Path:  fmt.Sprintf("/%s/key", suite.storeKey.Name()), // required path to get key/value+proof
+		Data:  []byte("MYKEY"),
+		Prove: true,
+	})
+	require.NotNil(suite.T(), res.Proof)
+
+	proof := types.MerkleProof{
+		Proof: res.Proof,
+	}
+	root := types.NewMerkleRoot(cid.Hash)
+	path := types.NewMerklePath([]string{suite.storeKey.Name(), "MYKEY"})
+
+	cases := []struct {
+		name    string
+		value   []byte
+		salt    []byte
+		expPass bool
+	}{
+		{"valid salt", []byte("MYVALUE"), salt, true},
+		{"salt in the value", []byte("ibc-leaf:MYVALUE"), nil, true},
+		{"no salt", []byte("MYVALUE"), nil, false},
+		{"wrong salt", []byte("MYVALUE"), []byte("ibc-node:"), false},
+		{"salt as suffix", []byte("ibc-leaf:"), []byte("MYVALUE"), false},
+		{"wrong value", []byte("MYOTHERVALUE"), salt, false},
+		{"empty value", nil, []byte("ibc-leaf:MYVALUE"), false},
+	}
+
+	for i, tc := range cases {
+		err := proof.VerifyMembershipSalted(&root, path, tc.value, tc.salt)
+		if tc.expPass {
+			suite.Require().NoError(err, "test case %d: %s", i, tc.name)
+		} else {
+			suite.Require().Error(err, "test case %d: %s", i, tc.name)
+		}
+	}
+
+	// the salt is not modified
+	suite.Require().Equal([]byte("ibc-leaf:"), salt)
+}
+
+func (suite *MerkleTestSuite) TestVerifyMembershipHashedPath() {
+	hashedKey := sha256.Sum256([]byte("clients/clientzero/clientState"))
+	suite.iavlStore.Set(hashedKey[:], []byte("MYVALUE"))
+	cid := suite.store.Commit()
+
+	res := suite.store.Query(abci.RequestQuery{
+		Path:   fmt.Sprintf("/%s/key", suite.storeKey.Name()), // required path to get key/value+proof
+		Data:   hashedKey[:],
+		Height: cid.Version,
+		Prove:  true,
+	})
+	require.NotNil(suite.T(), res.Proof)
+
+	proof := types.MerkleProof{
+		Proof: res.Proof,
+	}
+	root := types.NewMerkleRoot(cid.Hash)
+	path, err := types.ApplyPrefix(types.NewMerklePrefix([]byte(suite.storeKey.Name())), "clients/clientzero/clientState")
+	suite.Require().NoError(err)
+
+	// the unhashed path is not the committed key
+	suite.Require().Error(proof.VerifyMembership(&root, path, []byte("MYVALUE")))
+
+	cases := []struct {
+		name    string
+		path    exported.Path
+		value   []byte
+		expPass bool
+	}{
+		{"hashed path", path, []byte("MYVALUE"), true},
+		{"hashed path split in keys", types.NewMerklePath([]string{suite.storeKey.Name(), "clients", "clientzero", "clientState"}), []byte("MYVALUE"), true},
+		{"wrong value", path, []byte("MYOTHERVALUE"), false},
+		{"other path", types.NewMerklePath([]string{suite.storeKey.Name(), "clients/clientone/clientState"}), []byte("MYVALUE"), false},
+		{"other store", types.NewMerklePath([]string{"otherStoreKey", "clients/clientzero/clientState"}), []byte("MYVALUE"), false},
+		{"store key only", types.NewMerklePath([]string{suite.storeKey.Name()}), []byte("MYVALUE"), false},
+		{"not a merkle path", nil, []byte("MYVALUE"), false},
+	}
+
+	for i, tc := range cases {
+		err := proof.VerifyMembershipHashedPath(&root, tc.path, tc.value)
+		if tc.expPass {
+			suite.Require().NoError(err, "test case %d: %s", i, tc.name)
+		} else {
+			suite.Require().Error(err, "test case %d: %s", i, tc.name)
+		}
+	}
+}
+
+func (suite *MerkleTestSuite) TestVerifyMembershipAnySpecOrder() {
+	suite.iavlStore.Set([]byte("MYKEY"), []byte("MYVALUE"))
+	cid := suite.store.Commit()
+
+	res := suite.store.Query(abci.RequestQuery{
+		Path:  fmt.Sprintf("/%s/key", suite.storeKey.Name()), // required path to get key/value+proof
+		Data:  []byte("MYKEY"),
+		Prove: true,
+	})
+	require.NotNil(suite.T(), res.Proof)
+	require.Len(suite.T(), res.Proof.Ops, 2)
+
+	proof := types.MerkleProof{
+		Proof: res.Proof,
+	}
+	reversed := types.MerkleProof{
+		Proof: &merkle.Proof{Ops: []merkle.ProofOp{res.Proof.Ops[1], res.Proof.Ops[0]}},
+	}
+	root := types.NewMerkleRoot(cid.Hash)
+	path := types.NewMerklePath([]string{suite.storeKey.Name(), "MYKEY"})
+
+	// the reversed proof only verifies when retried in the original order
+	suite.Require().Error(reversed.VerifyMembership(&root, path, []byte("MYVALUE")))
+
+	cases := []struct {
+		name     string
+		proof    types.MerkleProof
+		value    []byte
+		expOrder string
+		expPass  bool
+	}{
+		{"lowest subtree first", proof, []byte("MYVALUE"), "order=as-is", true},
+		{"root first", reversed, []byte("MYVALUE"), "order=reversed", true},
+		{"lowest subtree first, wrong value", proof, []byte("WRONGVALUE"), "", false},
+		{"root first, wrong value", reversed, []byte("WRONGVALUE"), "", false},
+	}
+
+	for i, tc := range cases {
+		tc := tc
+		suite.Run(tc.name, func() {
+			var buf bytes.Buffer
+			logger := log.NewTMLogger(log.NewSyncWriter(&buf))
+
+			err := tc.proof.VerifyMembershipAnySpecOrder(logger, &root, path, tc.value)
+
+			if tc.expPass {
+				// nolint: scopelint
+				suite.Require().NoError(err, "test case %d should have passed", i)
+				suite.Require().Contains(buf.String(), tc.expOrder)
+			} else {
+				// nolint: scopelint
+				suite.Require().Error(err, "test case %d should have failed", i)
+				suite.Require().Empty(buf.String())
+			}
+		})
+	}
+
+	// nil logger
+	suite.Require().NoError(reversed.VerifyMembershipAnySpecOrder(nil, &root, path, []byte("MYVALUE")))
+}
+
 func (suite *MerkleTestSuite) TestVerifyNonMembership() {
 	suite.iavlStore.Set([]byte("MYKEY"), []byte("MYVALUE"))
 	cid := suite.store.Commit()
@@ -99,6 +338,7 @@ func (suite *MerkleTestSuite) TestVerifyNonMembership() {
 		{"wrong storeKey", cid.Hash, []string{"otherStoreKey", "MYABSENTKEY"}, false},              // invalid proof with wrong store prefix
 		{"wrong root", []byte("WRONGROOT"), []string{suite.storeKey.Name(), "MYABSENTKEY"}, false}, // invalid proof with wrong root
 		{"nil root", []byte(nil), []string{suite.storeKey.Name(), "MYABSENTKEY"}, false},           // invalid proof with nil root
+		{"zero root", make([]byte, 32), []string{suite.storeKey.Name(), "MYABSENTKEY"}, false},     // invalid proof with zero root
 	}
 
 	for i, tc := range cases {
@@ -122,6 +362,371 @@ func (suite *MerkleTestSuite) TestVerifyNonMembership() {
 
 }
 
+func TestVerifyMembershipEitherPrefix(t *testing.T) {
+	store := rootmulti.NewStore(dbm.NewMemDB())
+	oldKey := storetypes.NewKVStoreKey("oldstore")
+	newKey := storetypes.NewKVStoreKey("newstore")
+	store.MountStoreWithDB(oldKey, storetypes.StoreTypeIAVL, nil)
+	store.MountStoreWithDB(newKey, storetypes.StoreTypeIAVL, nil)
+	require.NoError(t, store.LoadVersion(0))
+
+	// the key was migrated from the old store to the new one, both proofs verify
+	// against the same root during the transition
+	store.GetCommitKVStore(oldKey).Set([]byte("clients/client/clientState"), []byte("MYVALUE"))
+	store.GetCommitKVStore(newKey).Set([]byte("clients/client/clientState"), []byte("MYVALUE"))
+	cid := store.Commit()
+	root := types.NewMerkleRoot(cid.Hash)
+
+	query := func(storeName string) types.MerkleProof {
+		res := store.Query(abci.RequestQuery{
+			Path:   fmt.Sprintf("/%s/key", storeName), // required path to get key/value+proof
+			Data:   []byte("clients/client/clientState"),
+			Height: cid.Version,
+			Prove:  true,
+		})
+		require.NotNil(t, res.Proof)
+		return types.MerkleProof{Proof: res.Proof}
+	}
+
+	oldProof := query(oldKey.Name())
+	newProof := query(newKey.Name())
+	oldPrefix := types.NewMerklePrefix([]byte(oldKey.Name()))
+	newPrefix := types.NewMerklePrefix([]byte(newKey.Name()))
+	rawPath := types.NewMerklePath([]string{"clients/client/clientState"})
+
+	cases := []struct {
+		name     string
+		proof    types.MerkleProof
+		prefixes []types.MerklePrefix
+		rawPath  types.MerklePath
+		value    []byte
+		expPass  bool
+	}{
+		{"old proof, old and new prefixes", oldProof, []types.MerklePrefix{oldPrefix, newPrefix}, rawPath, []byte("MYVALUE"), true},
+		{"new proof, old and new prefixes", newProof, []types.MerklePrefix{oldPrefix, newPrefix}, rawPath, []byte("MYVALUE"), true},
+		{"new proof, new and old prefixes", newProof, []types.MerklePrefix{newPrefix, oldPrefix}, rawPath, []byte("MYVALUE"), true},
+		{"new proof, old prefix only", newProof, []types.MerklePrefix{oldPrefix}, rawPath, []byte("MYVALUE"), false},
+		{"old proof, new prefix only", oldProof, []types.MerklePrefix{newPrefix}, rawPath, []byte("MYVALUE"), false},
+		{"wrong value", newProof, []types.MerklePrefix{oldPrefix, newPrefix}, rawPath, []byte("WRONGVALUE"), false},
+		{"wrong raw path", newProof, []types.MerklePrefix{oldPrefix, newPrefix}, types.NewMerklePath([]string{"clients/other/clientState"}), []byte("MYVALUE"), false},
+		{"no prefixes", newProof, nil, rawPath, []byte("MYVALUE"), false},
+		{"empty prefix", newProof, []types.MerklePrefix{types.NewMerklePrefix(nil), newPrefix}, rawPath, []byte("MYVALUE"), false},
+		{"empty raw path", newProof, []types.MerklePrefix{oldPrefix, newPrefix}, types.MerklePath{}, []byte("MYVALUE"), false},
+	}
+
+	for i, tc := range cases {
+		err := tc.proof.VerifyMembershipEitherPrefix(&root, tc.prefixes, tc.rawPath, tc.value)
+		if tc.expPass {
+			require.NoError(t, err, "test case %d: %s", i, tc.name)
+		} else {
+			require.Error(t, err, "test case %d: %s", i, tc.name)
+		}
+	}
+
+	// the raw path is not modified by the prefixes
+	require.Equal(t, []string{"clients/client/clientState"}, rawPath.Segments())
+}
+
+func TestMerkleRootMarshalBinary(t *testing.T) {
+	roots := []types.MerkleRoot{
+		types.NewMerkleRoot([]byte("root hash")),
+		types.NewMerkleRoot(make([]byte, 32)),
+		types.NewMerkleRoot([]byte{0x01}),
+		types.NewMerkleRoot(nil),
+	}
+
+	for _, root := range roots {
+		bz, err := root.MarshalBinary()
+		require.NoError(t, err)
+		require.Len(t, bz, 1+len(root.Hash))
+		require.Equal(t, byte(exported.Merkle), bz[0])
+
+		var decoded types.MerkleRoot
+		require.NoError(t, decoded.UnmarshalBinary(bz))
+		require.Equal(t, root, decoded)
+	}
+
+	// the decoded hash doesn't alias the encoded bytes
+	bz, err := types.NewMerkleRoot([]byte("root hash")).MarshalBinary()
+	require.NoError(t, err)
+	var decoded types.MerkleRoot
+	require.NoError(t, decoded.UnmarshalBinary(bz))
+	bz[1] = 'R'
+	require.Equal(t, []byte("root hash"), decoded.GetHash())
+
+	// a decoded root overwrites the previous one
+	require.NoError(t, decoded.UnmarshalBinary([]byte{byte(exported.Merkle)}))
+	require.True(t, decoded.IsEmpty())
+
+	require.Error(t, decoded.UnmarshalBinary(nil))
+	require.Error(t, decoded.UnmarshalBinary([]byte{0x00, 0x01}))
+	require.Error(t, decoded.UnmarshalBinary([]byte("root hash")))
+}
+
+func TestMerkleRootIsZero(t *testing.T) {
+	cases := []struct {
+		name    string
+		hash    []byte
+		isEmpty bool
+		isZero  bool
+	}{
+		{"nil root", nil, true, false},
+		{"empty root", []byte{}, true, false},
+		{"all-zero root", make([]byte, 32), false, true},
+		{"valid root", append(make([]byte, 31), 1), false, false},
+	}
+
+	for _, tc := range cases {
+		root := types.NewMerkleRoot(tc.hash)
+		require.Equal(t, tc.isEmpty, root.IsEmpty(), tc.name)
+		require.Equal(t, tc.isZero, root.IsZero(), tc.name)
+	}
+}
+
+func (suite *MerkleTestSuite) TestVerifyTombstone() {
+	tombstone := []byte{0x00}
+
+	suite.iavlStore.Set([]byte("MYKEY"), tombstone)
+	suite.iavlStore.Set([]byte("MYLIVEKEY"), []byte("MYVALUE"))
+	cid := suite.store.Commit()
+	root := types.NewMerkleRoot(cid.Hash)
+
+	query := func(key string) types.MerkleProof {
+		res := suite.store.Query(abci.RequestQuery{
+			Path:  fmt.Sprintf("/%s/key", suite.storeKey.Name()), // required path to get key/value+proof
+			Data:  []byte(key),
+			Prove: true,
+		})
+		suite.Require().NotNil(res.Proof)
+		return types.MerkleProof{Proof: res.Proof}
+	}
+
+	tombstoneProof := query("MYKEY")
+	liveProof := query("MYLIVEKEY")
+	absentProof := query("MYABSENTKEY")
+
+	path := func(key string) types.MerklePath {
+		return types.NewMerklePath([]string{suite.storeKey.Name(), key})
+	}
+
+	cases := []struct {
+		name       string
+		proof      types.MerkleProof
+		path       types.MerklePath
+		tombstone  []byte
+		expErrPart string
+		expPass    bool
+	}{
+		{"tombstoned key", tombstoneProof, path("MYKEY"), tombstone, "", true},
+		{"live key", liveProof, path("MYLIVEKEY"), tombstone, "is not tombstoned", false},
+		{"truly absent key", absentProof, path("MYABSENTKEY"), tombstone, "is absent, not tombstoned", false},
+		{"wrong tombstone sentinel", tombstoneProof, path("MYKEY"), []byte{0x01}, "is not tombstoned", false},
+		{"wrong path", tombstoneProof, path("MYLIVEKEY"), tombstone, "is not tombstoned", false},
+		{"empty tombstone sentinel", tombstoneProof, path("MYKEY"), nil, "empty params or proof", false},
+		{"empty proof", types.MerkleProof{}, path("MYKEY"), tombstone, "empty params or proof", false},
+	}
+
+	for i, tc := range cases {
+		tc := tc
+		suite.Run(tc.name, func() {
+			err := tc.proof.VerifyTombstone(&root, tc.path, tc.tombstone)
+
+			if tc.expPass {
+				// nolint: scopelint
+				suite.Require().NoError(err, "test case %d should have passed", i)
+			} else {
+				// nolint: scopelint
+				suite.Require().Error(err, "test case %d should have failed", i)
+				suite.Require().Contains(err.Error(), tc.expErrPart)
+			}
+		})
+	}
+}
+
+func (suite *MerkleTestSuite) TestVerifyDeletionThenRecreation() {
+	query := func(height int64) types.MerkleProof {
+		res := suite.store.Query(abci.RequestQuery{
+			Path:   fmt.Sprintf("/%s/key", suite.storeKey.Name()), // required path to get key/value+proof
+			Data:   []byte("MYKEY"),
+			Height: height,
+			Prove:  true,
+		})
+		suite.Require().NotNil(res.Proof)
+		return types.MerkleProof{Proof: res.Proof}
+	}
+
+	suite.iavlStore.Set([]byte("MYKEY"), []byte("MYVALUE"))
+	suite.iavlStore.Set([]byte("MYOTHERKEY"), []byte("MYOTHERVALUE"))
+	cid := suite.store.Commit()
+	existProof, existRoot := query(cid.Version), types.NewMerkleRoot(cid.Hash)
+
+	suite.iavlStore.Delete([]byte("MYKEY"))
+	cid = suite.store.Commit()
+	delProof, delRoot := query(cid.Version), types.NewMerkleRoot(cid.Hash)
+
+	suite.iavlStore.Set([]byte("MYKEY"), []byte("MYNEWVALUE"))
+	cid = suite.store.Commit()
+	recreateProof, recreateRoot := query(cid.Version), types.NewMerkleRoot(cid.Hash)
+
+	path := types.NewMerklePath([]string{suite.storeKey.Name(), "MYKEY"})
+
+	cases := []struct {
+		name          string
+		delProof      types.MerkleProof
+		delRoot       types.MerkleRoot
+		recreateProof types.MerkleProof
+		recreateRoot  types.MerkleRoot
+		value         []byte
+		expPass       bool
+	}{
+		{"deleted then recreated", delProof, delRoot, recreateProof, recreateRoot, []byte("MYNEWVALUE"), true},
+		{"not deleted", existProof, existRoot, recreateProof, recreateRoot, []byte("MYNEWVALUE"), false},
+		{"deletion proof against wrong root", delProof, recreateRoot, recreateProof, recreateRoot, []byte("MYNEWVALUE"), false},
+		{"not recreated", delProof, delRoot, delProof, delRoot, []byte("MYNEWVALUE"), false},
+		{"recreated with old value", delProof, delRoot, recreateProof, recreateRoot, []byte("MYVALUE"), false},
+		{"recreation proof against wrong root", delProof, delRoot, recreateProof, delRoot, []byte("MYNEWVALUE"), false},
+	}
+
+	for i, tc := range cases {
+		tc := tc
+		suite.Run(tc.name, func() {
+			err := types.VerifyDeletionThenRecreation(tc.delProof, &tc.delRoot, tc.recreateProof, &tc.recreateRoot, path, tc.value)
+
+			if tc.expPass {
+				// nolint: scopelint
+				suite.Require().NoError(err, "test case %d should have passed", i)
+			} else {
+				// nolint: scopelint
+				suite.Require().Error(err, "test case %d should have failed", i)
+			}
+		})
+	}
+}
+
+func (suite *MerkleTestSuite) TestVerifyTransition() {
+	query := func(height int64) types.MerkleProof {
+		res := suite.store.Query(abci.RequestQuery{
+			Path:   fmt.Sprintf("/%s/key", suite.storeKey.Name()), // required path to get key/value+proof
+			Data:   []byte("MYKEY"),
+			Height: height,
+			Prove:  true,
+		})
+		suite.Require().NotNil(res.Proof)
+		return types.MerkleProof{Proof: res.Proof}
+	}
+
+	suite.iavlStore.Set([]byte("MYKEY"), []byte("MYVALUE"))
+	cid := suite.store.Commit()
+	beforeProof, beforeRoot := query(cid.Version), types.NewMerkleRoot(cid.Hash)
+
+	suite.iavlStore.Set([]byte("MYKEY"), []byte("MYNEWVALUE"))
+	cid = suite.store.Commit()
+	afterProof, afterRoot := query(cid.Version), types.NewMerkleRoot(cid.Hash)
+
+	path := types.NewMerklePath([]string{suite.storeKey.Name(), "MYKEY"})
+	before, after := []byte("MYVALUE"), []byte("MYNEWVALUE")
+
+	cases := []struct {
+		name        string
+		beforeProof types.MerkleProof
+		beforeRoot  types.MerkleRoot
+		afterProof  types.MerkleProof
+		afterRoot   types.MerkleRoot
+		before      []byte
+		after       []byte
+		expPass     bool
+	}{
+		{"value changed", beforeProof, beforeRoot, afterProof, afterRoot, before, after, true},
+		{"wrong before value", beforeProof, beforeRoot, afterProof, afterRoot, after, after, false},
+		{"wrong after value", beforeProof, beforeRoot, afterProof, afterRoot, before, before, false},
+		{"swapped roots", beforeProof, afterRoot, afterProof, beforeRoot, before, after, false},
+		{"swapped proofs", afterProof, beforeRoot, beforeProof, afterRoot, before, after, false},
+		{"reversed transition", afterProof, afterRoot, beforeProof, beforeRoot, before, after, false},
+		{"empty before proof", types.MerkleProof{}, beforeRoot, afterProof, afterRoot, before, after, false},
+		{"empty after value", beforeProof, beforeRoot, afterProof, afterRoot, before, nil, false},
+	}
+
+	for i, tc := range cases {
+		tc := tc
+		suite.Run(tc.name, func() {
+			err := types.VerifyTransition(tc.beforeProof, &tc.beforeRoot, tc.afterProof, &tc.afterRoot, path, tc.before, tc.after)
+
+			if tc.expPass {
+				// nolint: scopelint
+				suite.Require().NoError(err, "test case %d should have passed", i)
+			} else {
+				// nolint: scopelint
+				suite.Require().Error(err, "test case %d should have failed", i)
+			}
+		})
+	}
+}
+
+func TestPrefixForModule(t *testing.T) {
+	require.Equal(t, types.NewMerklePrefix([]byte("ibc")), types.PrefixForModule(host.StoreKey))
+	require.Equal(t, []byte("bank"), types.PrefixForModule("bank").Bytes())
+	require.Equal(t, []byte("staking"), types.PrefixForModule("staking").Bytes())
+	require.True(t, types.PrefixForModule("").IsEmpty())
+
+	// the prefix matches the substore the module's state is committed under
+	db := dbm.NewMemDB()
+	store := rootmulti.NewStore(db)
+	storeKey := storetypes.NewKVStoreKey("bank")
+	store.MountStoreWithDB(storeKey, storetypes.StoreTypeIAVL, nil)
+	require.NoError(t, store.LoadVersion(0))
+
+	store.GetCommitKVStore(storeKey).Set([]byte("supply/stake"), []byte("1000"))
+	cid := store.Commit()
+
+	res := store.Query(abci.RequestQuery{
+		Path:   fmt.Sprintf("/%s/key", storeKey.Name()), // required path to get key/value+proof
+		Data:   []byte("supply/stake"),
+		Height: cid.Version,
+		Prove:  true,
+	})
+	require.NotNil(t, res.Proof)
+
+	prefix := types.PrefixForModule("bank")
+	path, err := types.ApplyPrefix(&prefix, "supply/stake")
+	require.NoError(t, err)
+
+	proof := types.MerkleProof{Proof: res.Proof}
+	root := types.NewMerkleRoot(cid.Hash)
+	require.NoError(t, proof.VerifyMembership(&root, path, []byte("1000")))
+}
+
+func TestMerklePrefixAppend(t *testing.T) {
+	cases := []struct {
+		name string
+		a, b []byte
+	}{
+		{"client and sub prefixes", []byte("ibc"), []byte("clients")},
+		{"empty sub prefix", []byte("ibc"), nil},
+		{"empty prefix", nil, []byte("clients")},
+		{"both empty", nil, nil},
+	}
+
+	for _, tc := range cases {
+		a, b := types.NewMerklePrefix(tc.a), types.NewMerklePrefix(tc.b)
+		expected := append(append([]byte{}, a.Bytes()...), b.Bytes()...)
+
+		require.Equal(t, expected, a.Append(b).Bytes(), tc.name)
+		require.Equal(t, tc.a, a.Bytes(), "%s: prefix was modified", tc.name)
+		require.Equal(t, tc.b, b.Bytes(), "%s: other prefix was modified", tc.name)
+	}
+
+	// the order of the prefixes is kept
+	a, b := types.NewMerklePrefix([]byte("ibc")), types.NewMerklePrefix([]byte("clients"))
+	require.Equal(t, []byte("ibcclients"), a.Append(b).Bytes())
+	require.Equal(t, []byte("clientsibc"), b.Append(a).Bytes())
+
+	// appending to a prefix with spare capacity doesn't alias the result
+	base := types.NewMerklePrefix(make([]byte, 3, 16))
+	first, second := base.Append(a), base.Append(b)
+	require.Equal(t, []byte("\x00\x00\x00ibc"), first.Bytes())
+	require.Equal(t, []byte("\x00\x00\x00clients"), second.Bytes())
+}
+
 func TestApplyPrefix(t *testing.T) {
 	prefix := types.NewMerklePrefix([]byte("storePrefixKey"))
 
@@ -139,3 +744,409 @@ func TestApplyPrefix(t *testing.T) {
 	require.NotNil(t, err, "invalid prefix does not returns error")
 	require.Equal(t, types.MerklePath{}, invalidPath, "invalid prefix returns valid Path on ApplyPrefix")
 }
+
+func TestApplyPrefixLengthPrefixed(t *testing.T) {
+	// plain concatenation of these prefixes and paths commits to the same key
+	accPrefix := types.NewMerklePrefix([]byte("acc"))
+	accountPrefix := types.NewMerklePrefix([]byte("account"))
+
+	accPath, err := types.ApplyPrefixLengthPrefixed(accPrefix, "ountstore/key")
+	require.NoError(t, err)
+	accountPath, err := types.ApplyPrefixLengthPrefixed(accountPrefix, "store/key")
+	require.NoError(t, err)
+
+	require.Equal(t, "/\x03acc/\rountstore/key", accPath.Pretty())
+	require.Equal(t, "/\x07account/\tstore/key", accountPath.Pretty())
+	require.NotEqual(t, strings.ReplaceAll(accPath.Pretty(), "/", ""), strings.ReplaceAll(accountPath.Pretty(), "/", ""))
+
+	_, err = types.ApplyPrefixLengthPrefixed(accPrefix, "invalid-path/doesitfail?/hopefully")
+	require.Error(t, err)
+
+	_, err = types.ApplyPrefixLengthPrefixed(types.MerklePrefix{}, "store/key")
+	require.Error(t, err)
+}
+
+func TestMerklePathMatches(t *testing.T) {
+	packetPath := "commitments/ports/transfer/channels/channel-0/packets/1"
+	path, err := types.ApplyPrefix(types.NewMerklePrefix([]byte("ibc")), packetPath)
+	require.NoError(t, err)
+
+	cases := []struct {
+		name     string
+		pattern  []string
+		expMatch bool
+	}{
+		{"exact path", []string{"ibc", packetPath}, true},
+		{"wildcard last segment", []string{"ibc", "commitments/ports/transfer/channels/channel-0/packets/*"}, true},
+		{"wildcard middle segments", []string{"ibc", "commitments/ports/*/channels/*/packets/1"}, true},
+		{"wildcard prefix", []string{"*", packetPath}, true},
+		{"different sequence", []string{"ibc", "commitments/ports/transfer/channels/channel-0/packets/2"}, false},
+		{"wildcard middle segment, different port", []string{"ibc", "commitments/ports/bank/channels/*/packets/*"}, false},
+		{"wildcard does not match several segments", []string{"ibc", "commitments/ports/*/packets/1"}, false},
+		{"wildcard does not match a whole key", []string{"ibc", "*"}, false},
+		{"fewer keys", []string{"ibc"}, false},
+		{"more keys", []string{"ibc", packetPath, "*"}, false},
+		{"different prefix", []string{"bank", packetPath}, false},
+	}
+
+	for _, tc := range cases {
+		pattern := types.NewMerklePath(tc.pattern)
+		require.Equal(t, tc.expMatch, path.Matches(pattern), tc.name)
+	}
+}
+
+func TestMerklePathSuffixEqual(t *testing.T) {
+	path, err := types.ApplyPrefix(types.NewMerklePrefix([]byte("ibc")), "clients/clientA/consensusState/5")
+	require.NoError(t, err)
+
+	cases := []struct {
+		name      string
+		other     []string
+		suffixLen int
+		expEqual  bool
+	}{
+		{"same path", []string{"ibc", "clients/clientA/consensusState/5"}, 5, true},
+		{"different store prefix", []string{"other", "clients/clientA/consensusState/5"}, 4, true},
+		{"different client prefix", []string{"ibc", "clients/clientB/consensusState/5"}, 2, true},
+		{"suffix split in keys", []string{"other", "clients", "clientB", "consensusState", "5"}, 2, true},
+		{"more prefix segments", []string{"ibc", "connections/connection-0/clients/clientB/consensusState/5"}, 2, true},
+		{"suffix includes the different client", []string{"ibc", "clients/clientB/consensusState/5"}, 3, false},
+		{"different last segment", []string{"ibc", "clients/clientA/consensusState/6"}, 1, false},
+		{"suffix longer than the path", []string{"ibc", "clients/clientA/consensusState/5"}, 6, false},
+		{"suffix longer than the other path", []string{"consensusState/5"}, 3, false},
+		{"zero suffix length", []string{"ibc", "clients/clientA/consensusState/5"}, 0, false},
+		{"negative suffix length", []string{"ibc", "clients/clientA/consensusState/5"}, -1, false},
+		{"empty other path", nil, 1, false},
+	}
+
+	for _, tc := range cases {
+		other := types.NewMerklePath(tc.other)
+		require.Equal(t, tc.expEqual, path.SuffixEqual(other, tc.suffixLen), tc.name)
+		require.Equal(t, tc.expEqual, other.SuffixEqual(path, tc.suffixLen), tc.name)
+	}
+}
+
+func TestMerklePathSegments(t *testing.T) {
+	path, err := types.ApplyPrefix(types.NewMerklePrefix([]byte("ibc")), "connections/connection-0")
+	require.NoError(t, err)
+	require.Equal(t, []string{"ibc", "connections/connection-0"}, path.Segments())
+
+	// keys are decoded regardless of their encoding
+	keyPath := types.KeyPath{}
+	keyPath = keyPath.AppendKey([]byte("log"), types.URL)
+	keyPath = keyPath.AppendKey([]byte{0x00, 0xff}, types.HEX)
+	path = types.MerklePath{KeyPath: keyPath}
+	require.Equal(t, []string{"log", "\x00\xff"}, path.Segments())
+
+	path = types.NewMerklePath([]string{"ibc", "key with spaces", "%2F"})
+	require.Equal(t, []string{"ibc", "key with spaces", "%2F"}, path.Segments())
+
+	require.Empty(t, types.MerklePath{}.Segments())
+}
+
+func TestMerklePathHash(t *testing.T) {
+	path := types.NewMerklePath([]string{"ibc", "connections/connection-0"})
+	prefixed, err := types.ApplyPrefix(types.NewMerklePrefix([]byte("ibc")), "connections/connection-0")
+	require.NoError(t, err)
+
+	// the same keys under different encodings hash equally
+	keyPath := types.KeyPath{}
+	keyPath = keyPath.AppendKey([]byte("ibc"), types.HEX)
+	keyPath = keyPath.AppendKey([]byte("connections/connection-0"), types.URL)
+	hexPath := types.MerklePath{KeyPath: keyPath}
+
+	require.Equal(t, path.Hash(), path.Hash())
+	require.Equal(t, path.Hash(), prefixed.Hash())
+	require.Equal(t, path.Hash(), hexPath.Hash())
+
+	// the hash is usable as a map key
+	proofs := map[[32]byte]string{path.Hash(): "proof"}
+	require.Equal(t, "proof", proofs[hexPath.Hash()])
+
+	// the keys boundaries and order are part of the hash
+	differentPaths := []types.MerklePath{
+		types.NewMerklePath([]string{"ibcconnections/connection-0"}),
+		types.NewMerklePath([]string{"ibc", "connections", "connection-0"}),
+		types.NewMerklePath([]string{"connections/connection-0", "ibc"}),
+		types.NewMerklePath([]string{"ibc", "connections/connection-1"}),
+		types.NewMerklePath([]string{"ibc"}),
+		{},
+	}
+	for _, other := range differentPaths {
+		require.NotEqual(t, path.Hash(), other.Hash(), other.String())
+	}
+
+	require.Equal(t, types.MerklePath{}.Hash(), types.NewMerklePath([]string{}).Hash())
+}
+
+func TestMerklePathLastN(t *testing.T) {
+	path := types.NewMerklePath([]string{"ibc", "ports", "transfer", "channels", "channel-0"})
+
+	cases := []struct {
+		name      string
+		n         int
+		expPretty string
+	}{
+		{"last key", 1, "/channel-0"},
+		{"last keys", 3, "/transfer/channels/channel-0"},
+		{"all keys", 5, "/ibc/ports/transfer/channels/channel-0"},
+		{"more than all keys", 6, "/ibc/ports/transfer/channels/channel-0"},
+		{"zero keys", 0, ""},
+		{"negative keys", -1, ""},
+	}
+
+	for _, tc := range cases {
+		require.Equal(t, tc.expPretty, path.LastN(tc.n).Pretty(), tc.name)
+	}
+
+	require.True(t, path.LastN(0).IsEmpty())
+	require.Equal(t, "/ibc/ports/transfer/channels/channel-0", path.Pretty(), "original path was modified")
+}
+
+func TestStringRFC3986(t *testing.T) {
+	// expected values are the segments as escaped by the Rust relayer
+	cases := []struct {
+		name     string
+		pathArr  []string
+		expected string
+	}{
+		{"unreserved", []string{"ibc", "a-b.c_d~e"}, "/ibc/a-b.c_d~e"},
+		{"space", []string{"ibc", "a b"}, "/ibc/a%20b"},
+		{"slash", []string{"ibc", "ports/transfer"}, "/ibc/ports%2Ftransfer"},
+		{"sub-delims", []string{"ibc", "key+1:a@b"}, "/ibc/key%2B1%3Aa%40b"},
+		{"utf8", []string{"ibc", "é"}, "/ibc/%C3%A9"},
+	}
+
+	for _, tc := range cases {
+		path := types.NewMerklePath(tc.pathArr)
+		require.Equal(t, tc.expected, path.StringRFC3986(), tc.name)
+
+		// both encodings decode to the same keys used during verification
+		keys, err := merkle.KeyPathToKeys(path.String())
+		require.NoError(t, err, tc.name)
+		rfcKeys, err := merkle.KeyPathToKeys(path.StringRFC3986())
+		require.NoError(t, err, tc.name)
+		require.Equal(t, keys, rfcKeys, tc.name)
+	}
+}
+
+func (suite *MerkleTestSuite) TestVerifyMembershipRFC3986() {
+	key := "MY KEY+1"
+	suite.iavlStore.Set([]byte(key), []byte("MYVALUE"))
+	cid := suite.store.Commit()
+
+	res := suite.store.Query(abci.RequestQuery{
+		Path:  fmt.Sprintf("/%s/key", suite.storeKey.Name()), // required path to get key/value+proof
+		Data:  []byte(key),
+		Prove: true,
+	})
+	suite.Require().NotNil(res.Proof)
+
+	path := types.NewMerklePath([]string{suite.storeKey.Name(), key})
+	suite.Require().NotEqual(path.String(), path.StringRFC3986())
+
+	runtime := rootmulti.DefaultProofRuntime()
+	suite.Require().NoError(runtime.VerifyValue(res.Proof, cid.Hash, path.String(), []byte("MYVALUE")))
+	suite.Require().NoError(runtime.VerifyValue(res.Proof, cid.Hash, path.StringRFC3986(), []byte("MYVALUE")))
+}
+
+func TestSemanticEqual(t *testing.T) {
+	db := dbm.NewMemDB()
+	store := rootmulti.NewStore(db)
+	storeKey := storetypes.NewKVStoreKey("iavlStoreKey")
+	otherStoreKey := storetypes.NewKVStoreKey("otherStoreKey")
+	store.MountStoreWithDB(storeKey, storetypes.StoreTypeIAVL, nil)
+	store.MountStoreWithDB(otherStoreKey, storetypes.StoreTypeIAVL, nil)
+	require.NoError(t, store.LoadVersion(0))
+
+	iavlStore := store.GetCommitStore(storeKey).(*iavl.Store)
+	iavlStore.Set([]byte("MYKEY"), []byte("MYVALUE"))
+	iavlStore.Set([]byte("MYOTHERKEY"), []byte("MYOTHERVALUE"))
+	store.GetCommitStore(otherStoreKey).(*iavl.Store).Set([]byte("KEY"), []byte("VALUE"))
+	store.Commit()
+
+	query := func(key string) types.MerkleProof {
+		res := store.Query(abci.RequestQuery{
+			Path:  fmt.Sprintf("/%s/key", storeKey.Name()), // required path to get key/value+proof
+			Data:  []byte(key),
+			Prove: true,
+		})
+		require.NotNil(t, res.Proof)
+		return types.MerkleProof{Proof: res.Proof}
+	}
+
+	proof := query("MYKEY")
+
+	// re-encode the multistore operation with its store infos in reverse order
+	op, err := rootmulti.MultiStoreProofOpDecoder(proof.Proof.Ops[1])
+	require.NoError(t, err)
+	msOp := op.(rootmulti.MultiStoreProofOp)
+	infos := msOp.Proof.StoreInfos
+	require.Len(t, infos, 2)
+	infos[0], infos[1] = infos[1], infos[0]
+
+	reordered := types.MerkleProof{
+		Proof: &merkle.Proof{
+			Ops: []merkle.ProofOp{proof.Proof.Ops[0], rootmulti.NewMultiStoreProofOp(msOp.GetKey(), msOp.Proof).ProofOp()},
+		},
+	}
+	require.False(t, bytes.Equal(proof.Proof.Ops[1].Data, reordered.Proof.Ops[1].Data))
+	require.False(t, proof.Equal(reordered))
+
+	require.True(t, proof.SemanticEqual(proof))
+	require.True(t, proof.SemanticEqual(reordered))
+	require.True(t, reordered.SemanticEqual(proof))
+
+	require.False(t, proof.SemanticEqual(query("MYOTHERKEY")))
+	require.False(t, proof.SemanticEqual(query("MYABSENTKEY")))
+	require.False(t, proof.SemanticEqual(types.MerkleProof{}))
+	require.True(t, types.MerkleProof{}.SemanticEqual(types.MerkleProof{}))
+}
+
+func (suite *MerkleTestSuite) TestVerifyMembershipSingleOperation() {
+	suite.iavlStore.Set([]byte("MYKEY"), []byte("MYVALUE"))
+	cid := suite.store.Commit()
+
+	// query the substore directly to get a proof with a single operation
+	res := suite.iavlStore.Query(abci.RequestQuery{
+		Path:  "/key",
+		Data:  []byte("MYKEY"),
+		Prove: true,
+	})
+	suite.Require().NotNil(res.Proof)
+	suite.Require().Len(res.Proof.Ops, 1)
+
+	proof := types.MerkleProof{
+		Proof: res.Proof,
+	}
+	substoreRoot := suite.iavlStore.LastCommitID().Hash
+
+	cases := []struct {
+		name    string
+		root    []byte
+		pathArr []string
+		value   []byte
+		expPass bool
+	}{
+		{"valid proof", substoreRoot, []string{"MYKEY"}, []byte("MYVALUE"), true},
+		{"wrong value", substoreRoot, []string{"MYKEY"}, []byte("WRONGVALUE"), false},
+		{"wrong key", substoreRoot, []string{"NOTMYKEY"}, []byte("MYVALUE"), false},
+		{"path not consumed", substoreRoot, []string{suite.storeKey.Name(), "MYKEY"}, []byte("MYVALUE"), false},
+		{"multistore root", cid.Hash, []string{"MYKEY"}, []byte("MYVALUE"), false},
+		{"wrong root", []byte("WRONGROOT"), []string{"MYKEY"}, []byte("MYVALUE"), false},
+	}
+
+	for i, tc := range cases {
+		tc := tc
+		suite.Run(tc.name, func() {
+			root := types.NewMerkleRoot(tc.root)
+			path := types.NewMerklePath(tc.pathArr)
+
+			err := proof.VerifyMembership(&root, path, tc.value)
+
+			if tc.expPass {
+				// nolint: scopelint
+				suite.Require().NoError(err, "test case %d should have passed", i)
+			} else {
+				// nolint: scopelint
+				suite.Require().Error(err, "test case %d should have failed", i)
+			}
+		})
+	}
+}
+
+func BenchmarkVerifyMembershipSingleOperation(b *testing.B) {
+	store := rootmulti.NewStore(dbm.NewMemDB())
+	storeKey := storetypes.NewKVStoreKey("iavlStoreKey")
+	store.MountStoreWithDB(storeKey, storetypes.StoreTypeIAVL, nil)
+	require.NoError(b, store.LoadVersion(0))
+
+	iavlStore := store.GetCommitStore(storeKey).(*iavl.Store)
+	for i := 0; i < 1000; i++ {
+		iavlStore.Set([]byte(fmt.Sprintf("KEY%d", i)), []byte(fmt.Sprintf("VALUE%d", i)))
+	}
+	store.Commit()
+
+	res := iavlStore.Query(abci.RequestQuery{
+		Path:  "/key",
+		Data:  []byte("KEY500"),
+		Prove: true,
+	})
+	proof := types.MerkleProof{
+		Proof: res.Proof,
+	}
+	root := types.NewMerkleRoot(iavlStore.LastCommitID().Hash)
+	path := types.NewMerklePath([]string{"KEY500"})
+	value := []byte("VALUE500")
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := proof.VerifyMembership(&root, path, value); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func (suite *MerkleTestSuite) TestVerifyNonMembershipForgedBracket() {
+	for _, key := range []string{"KEY1", "KEY3", "KEY5", "KEY7"} {
+		suite.iavlStore.Set([]byte(key), []byte("VALUE"))
+	}
+	cid := suite.store.Commit()
+	root := types.NewMerkleRoot(cid.Hash)
+
+	query := func(key string) *merkle.Proof {
+		res := suite.store.Query(abci.RequestQuery{
+			Path:  fmt.Sprintf("/%s/key", suite.storeKey.Name()), // required path to get key/value+proof
+			Data:  []byte(key),
+			Prove: true,
+		})
+		suite.Require().NotNil(res.Proof)
+		return res.Proof
+	}
+
+	// forge replaces the key of the absence operation, keeping the neighbours
+	// that bracket the original key
+	forge := func(proof *merkle.Proof, key string) types.MerkleProof {
+		op, err := iavltree.AbsenceOpDecoder(proof.Ops[0])
+		suite.Require().NoError(err)
+
+		forgedOp := iavltree.NewAbsenceOp([]byte(key), op.(iavltree.AbsenceOp).Proof).ProofOp()
+		return types.MerkleProof{
+			Proof: &merkle.Proof{Ops: []merkle.ProofOp{forgedOp, proof.Ops[1]}},
+		}
+	}
+
+	// valid absence proofs for interior and edge keys
+	for _, key := range []string{"KEY2", "KEY0", "KEY9"} {
+		proof := types.MerkleProof{Proof: query(key)}
+		path := types.NewMerklePath([]string{suite.storeKey.Name(), key})
+		suite.Require().NoError(proof.VerifyNonMembership(&root, path), key)
+	}
+
+	absenceProof := query("KEY2")
+	cases := []struct {
+		name string
+		key  string
+	}{
+		{"key not bracketed by neighbours", "KEY4"},
+		{"key after the proven range", "KEY9"},
+		{"key exists on the neighbours", "KEY3"},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		suite.Run(tc.name, func() {
+			proof := forge(absenceProof, tc.key)
+			path := types.NewMerklePath([]string{suite.storeKey.Name(), tc.key})
+
+			err := proof.VerifyNonMembership(&root, path)
+			suite.Require().Error(err)
+			suite.Require().True(types.ErrInvalidAbsenceProof.Is(err), err.Error())
+		})
+	}
+
+	// existence proof provided where an absence proof is expected
+	existenceProof := types.MerkleProof{Proof: query("KEY3")}
+	err := existenceProof.VerifyNonMembership(&root, types.NewMerklePath([]string{suite.storeKey.Name(), "KEY3"}))
+	suite.Require().True(types.ErrInvalidAbsenceProof.Is(err))
+}
@@ -0,0 +1,74 @@
+package types
+
+import (
+	"errors"
+
+	"github.com/tendermint/iavl"
+	"github.com/tendermint/tendermint/crypto/merkle"
+
+	"github.com/cosmos/cosmos-sdk/store/rootmulti"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/cosmos/cosmos-sdk/x/ibc/23-commitment/exported"
+)
+
+// VerifyMembershipMetered verifies the membership of a merkle proof against the
+// given root, path and value, as VerifyMembership, and returns the gas used by
+// the verification: the number of nodes hashed by the proof operations that ran.
+// On failure, the gas used by the operations that ran before the failing one is
+// returned along with the error.
+func (proof MerkleProof) VerifyMembershipMetered(root exported.Root, path exported.Path, value []byte) (gasUsed uint64, err error) {
+	if proof.IsEmpty() || root == nil || root.IsEmpty() || path == nil || path.IsEmpty() || len(value) == 0 {
+		return 0, errors.New("empty params or proof")
+	}
+
+	if isZeroHash(root.GetHash()) {
+		return 0, errors.New("root hash cannot be zero")
+	}
+
+	runtime := rootmulti.DefaultProofRuntime()
+	poz, err := runtime.DecodeProof(proof.Proof)
+	if err != nil {
+		return 0, sdkerrors.Wrap(ErrInvalidProof, err.Error())
+	}
+
+	err = verifyOperators(poz, root.GetHash(), path.String(), [][]byte{value}, func(i int, _, _ []byte) {
+		gasUsed += operatorGas(poz[i])
+	})
+	return gasUsed, err
+}
+
+// operatorGas returns the number of nodes hashed by the proof operator to
+// compute its root, or one for unknown operators.
+func operatorGas(op merkle.ProofOperator) uint64 {
+	switch op := op.(type) {
+	case iavl.ValueOp:
+		if op.Proof == nil {
+			return 1
+		}
+
+		nodes := len(op.Proof.LeftPath) + len(op.Proof.Leaves)
+		for _, path := range op.Proof.InnerNodes {
+			nodes += len(path)
+		}
+		return uint64(nodes)
+
+	case rootmulti.MultiStoreProofOp:
+		if op.Proof == nil || len(op.Proof.StoreInfos) == 0 {
+			return 1
+		}
+
+		// a simple merkle tree of n leaves hashes 2n-1 nodes
+		return uint64(2*len(op.Proof.StoreInfos) - 1)
+
+	case merkle.SimpleValueOp:
+		if op.Proof == nil {
+			return 1
+		}
+
+		// the leaf and the ones hashed with each aunt
+		return uint64(len(op.Proof.Aunts) + 1)
+
+	default:
+		return 1
+	}
+}
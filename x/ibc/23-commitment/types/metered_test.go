@@ -0,0 +1,64 @@
+package types_test
+
+import (
+	"fmt"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+
+	"github.com/cosmos/cosmos-sdk/x/ibc/23-commitment/types"
+)
+
+func (suite *MerkleTestSuite) TestVerifyMembershipMetered() {
+	query := func(key string) types.MerkleProof {
+		res := suite.store.Query(abci.RequestQuery{
+			Path:   fmt.Sprintf("/%s/key", suite.storeKey.Name()), // required path to get key/value+proof
+			Data:   []byte(key),
+			Height: suite.store.LastCommitID().Version,
+			Prove:  true,
+		})
+		suite.Require().NotNil(res.Proof)
+		return types.MerkleProof{Proof: res.Proof}
+	}
+
+	path := types.NewMerklePath([]string{suite.storeKey.Name(), "MYKEY"})
+
+	// a single key store
+	suite.iavlStore.Set([]byte("MYKEY"), []byte("MYVALUE"))
+	cid := suite.store.Commit()
+	root := types.NewMerkleRoot(cid.Hash)
+
+	proof := query("MYKEY")
+	smallGas, err := proof.VerifyMembershipMetered(&root, path, []byte("MYVALUE"))
+	suite.Require().NoError(err)
+	suite.Require().True(smallGas >= uint64(len(proof.Proof.Ops)), "expected gas for each operation, got %d", smallGas)
+
+	// the gas used is deterministic
+	gas, err := proof.VerifyMembershipMetered(&root, path, []byte("MYVALUE"))
+	suite.Require().NoError(err)
+	suite.Require().Equal(smallGas, gas)
+
+	// a deeper tree takes more work to verify
+	for i := 0; i < 1000; i++ {
+		suite.iavlStore.Set([]byte(fmt.Sprintf("KEY%d", i)), []byte(fmt.Sprintf("VALUE%d", i)))
+	}
+	cid = suite.store.Commit()
+	root = types.NewMerkleRoot(cid.Hash)
+
+	proof = query("MYKEY")
+	largeGas, err := proof.VerifyMembershipMetered(&root, path, []byte("MYVALUE"))
+	suite.Require().NoError(err)
+	suite.Require().True(largeGas > smallGas, "expected more gas for a deeper tree: %d <= %d", largeGas, smallGas)
+
+	// the gas of the operations that ran is returned on failure
+	gas, err = proof.VerifyMembershipMetered(&root, types.NewMerklePath([]string{"otherstore", "MYKEY"}), []byte("MYVALUE"))
+	suite.Require().Error(err)
+	suite.Require().True(gas > 0 && gas < largeGas, "unexpected gas %d on failure", gas)
+
+	gas, err = proof.VerifyMembershipMetered(&root, path, []byte("WRONGVALUE"))
+	suite.Require().Error(err)
+	suite.Require().Equal(uint64(0), gas)
+
+	gas, err = types.MerkleProof{}.VerifyMembershipMetered(&root, path, []byte("MYVALUE"))
+	suite.Require().Error(err)
+	suite.Require().Equal(uint64(0), gas)
+}
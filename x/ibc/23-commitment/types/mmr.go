@@ -0,0 +1,181 @@
+package types
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"math/bits"
+
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/cosmos/cosmos-sdk/x/ibc/23-commitment/exported"
+)
+
+var _ exported.Proof = MMRProof{}
+
+// MMR hashes are domain separated so that a leaf can't be proven as an inner node
+// or the other way around.
+var (
+	mmrLeafPrefix = []byte{0}
+	mmrNodePrefix = []byte{1}
+)
+
+// MMRProof defines an inclusion proof of a leaf of a Merkle mountain range (MMR),
+// as used by chains committing to their history with an append-only MMR instead
+// of an IAVL tree. A leaf commits to a path and a value, the peaks are perfect
+// binary trees of decreasing heights, one per bit set in the number of leaves, and
+// the root bags the peaks from right to left:
+//
+//	leaf = sha256(0x00 || uvarint(len(path)) || path || uvarint(len(value)) || value)
+//	node = sha256(0x01 || left || right)
+//	root = node(peak_0, node(peak_1, ... node(peak_n-1, peak_n)))
+//
+// where the path is the string representation of the path. The root of an MMR
+// with a single peak is that peak.
+type MMRProof struct {
+	LeafIndex uint64   `json:"leaf_index" yaml:"leaf_index"` // index of the leaf, from the left of the MMR
+	LeafCount uint64   `json:"leaf_count" yaml:"leaf_count"` // number of leaves of the MMR
+	Siblings  [][]byte `json:"siblings" yaml:"siblings"`     // sibling hashes from the leaf up to its peak
+	Peaks     [][]byte `json:"peaks" yaml:"peaks"`           // hashes of the other peaks, from left to right
+}
+
+// NewMMRProof creates a new MMRProof instance
+func NewMMRProof(leafIndex, leafCount uint64, siblings, peaks [][]byte) MMRProof {
+	return MMRProof{
+		LeafIndex: leafIndex,
+		LeafCount: leafCount,
+		Siblings:  siblings,
+		Peaks:     peaks,
+	}
+}
+
+// GetCommitmentType implements ProofI. MMR roots are merkle roots.
+func (MMRProof) GetCommitmentType() exported.Type {
+	return exported.Merkle
+}
+
+// VerifyMembership verifies that the MMR of the given root commits to the given
+// path and value at the leaf index of the proof.
+func (proof MMRProof) VerifyMembership(root exported.Root, path exported.Path, value []byte) error {
+	if proof.IsEmpty() || root == nil || root.IsEmpty() || path == nil || path.IsEmpty() || len(value) == 0 {
+		return sdkerrors.Wrap(ErrInvalidProof, "empty params or proof")
+	}
+
+	if err := proof.ValidateBasic(); err != nil {
+		return err
+	}
+
+	computed := proof.computeRoot(mmrLeafHash(path.String(), value))
+	if !bytes.Equal(root.GetHash(), computed) {
+		return sdkerrors.Wrapf(ErrInvalidProof, "calculated MMR root is invalid: expected %X but got %X", root.GetHash(), computed)
+	}
+
+	return nil
+}
+
+// VerifyNonMembership implements ProofI. The leaves of an MMR are ordered by
+// insertion rather than by path, so the absence of a path can't be proven.
+func (MMRProof) VerifyNonMembership(exported.Root, exported.Path) error {
+	return sdkerrors.Wrap(ErrInvalidAbsenceProof, "MMR proofs cannot prove the absence of a path")
+}
+
+// IsEmpty returns true if the proof is of an MMR without leaves
+func (proof MMRProof) IsEmpty() bool {
+	return proof.LeafCount == 0
+}
+
+// ValidateBasic checks that the leaf is in the MMR and that the proof contains
+// one sibling per level of the peak of the leaf and one hash per other peak.
+func (proof MMRProof) ValidateBasic() error {
+	if proof.IsEmpty() {
+		return sdkerrors.Wrap(ErrInvalidProof, "MMR must contain at least one leaf")
+	}
+
+	if proof.LeafIndex >= proof.LeafCount {
+		return sdkerrors.Wrapf(ErrInvalidProof, "leaf index %d out of %d leaves", proof.LeafIndex, proof.LeafCount)
+	}
+
+	_, height := proof.leafPeak()
+	if len(proof.Siblings) != height {
+		return sdkerrors.Wrapf(ErrInvalidProof, "expected %d siblings, got %d", height, len(proof.Siblings))
+	}
+
+	if peaks := bits.OnesCount64(proof.LeafCount) - 1; len(proof.Peaks) != peaks {
+		return sdkerrors.Wrapf(ErrInvalidProof, "expected %d other peaks, got %d", peaks, len(proof.Peaks))
+	}
+
+	for _, hash := range append(append([][]byte{}, proof.Siblings...), proof.Peaks...) {
+		if len(hash) != sha256.Size {
+			return sdkerrors.Wrapf(ErrInvalidProof, "invalid hash length %d", len(hash))
+		}
+	}
+
+	return nil
+}
+
+// leafPeak returns the position, from the left, and the height of the peak that
+// contains the leaf of the proof. Each bit set in the number of leaves is a peak,
+// from the highest bit to the lowest.
+func (proof MMRProof) leafPeak() (position, height int) {
+	var start uint64
+	for height = 63; height >= 0; height-- {
+		size := uint64(1) << uint(height)
+		if proof.LeafCount&size == 0 {
+			continue
+		}
+
+		if proof.LeafIndex < start+size {
+			return position, height
+		}
+
+		start += size
+		position++
+	}
+
+	// unreachable for a leaf index lower than the number of leaves
+	return position, 0
+}
+
+// computeRoot computes the root of the MMR from the hash of the proven leaf.
+//
+// CONTRACT: the proof must be valid.
+func (proof MMRProof) computeRoot(leafHash []byte) []byte {
+	position, height := proof.leafPeak()
+
+	// the index of the leaf within its peak gives the side of each sibling
+	index := proof.LeafIndex & (uint64(1)<<uint(height) - 1)
+	hash := leafHash
+	for i, sibling := range proof.Siblings {
+		if index&(uint64(1)<<uint(i)) == 0 {
+			hash = mmrNodeHash(hash, sibling)
+		} else {
+			hash = mmrNodeHash(sibling, hash)
+		}
+	}
+
+	peaks := make([][]byte, 0, len(proof.Peaks)+1)
+	peaks = append(peaks, proof.Peaks[:position]...)
+	peaks = append(peaks, hash)
+	peaks = append(peaks, proof.Peaks[position:]...)
+
+	root := peaks[len(peaks)-1]
+	for i := len(peaks) - 2; i >= 0; i-- {
+		root = mmrNodeHash(peaks[i], root)
+	}
+
+	return root
+}
+
+func mmrLeafHash(path string, value []byte) []byte {
+	hasher := sha256.New()
+	hasher.Write(mmrLeafPrefix)
+	hasher.Write(lengthPrefix([]byte(path)))
+	hasher.Write(lengthPrefix(value))
+	return hasher.Sum(nil)
+}
+
+func mmrNodeHash(left, right []byte) []byte {
+	hasher := sha256.New()
+	hasher.Write(mmrNodePrefix)
+	hasher.Write(left)
+	hasher.Write(right)
+	return hasher.Sum(nil)
+}
@@ -0,0 +1,172 @@
+package types_test
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cosmos/cosmos-sdk/x/ibc/23-commitment/exported"
+	"github.com/cosmos/cosmos-sdk/x/ibc/23-commitment/types"
+)
+
+func mmrLeaf(path exported.Path, value []byte) []byte {
+	bz := []byte{0, byte(len(path.String()))}
+	bz = append(bz, path.String()...)
+	bz = append(bz, byte(len(value)))
+	bz = append(bz, value...)
+	hash := sha256.Sum256(bz)
+	return hash[:]
+}
+
+func mmrNode(left, right []byte) []byte {
+	hash := sha256.Sum256(append(append([]byte{1}, left...), right...))
+	return hash[:]
+}
+
+// mmrPerfectTree returns the root of the perfect binary tree of the given leaf
+// hashes and the siblings of the leaf at the given index, from the leaf up.
+func mmrPerfectTree(leaves [][]byte, index int) ([]byte, [][]byte) {
+	if len(leaves) == 1 {
+		return leaves[0], nil
+	}
+
+	half := len(leaves) / 2
+	left, leftSiblings := mmrPerfectTree(leaves[:half], index)
+	right, rightSiblings := mmrPerfectTree(leaves[half:], index-half)
+	if index < half {
+		return mmrNode(left, right), append(leftSiblings, right)
+	}
+	return mmrNode(left, right), append(rightSiblings, left)
+}
+
+// mmrProve returns the root of the MMR of the given leaf hashes and the proof of
+// the leaf at the given index.
+func mmrProve(leaves [][]byte, index int) ([]byte, types.MMRProof) {
+	proof := types.NewMMRProof(uint64(index), uint64(len(leaves)), nil, nil)
+
+	var peaks [][]byte
+	for start, size := 0, 1<<16; size > 0; size >>= 1 {
+		if len(leaves)&size == 0 {
+			continue
+		}
+
+		peak, siblings := mmrPerfectTree(leaves[start:start+size], index-start)
+		if index >= start && index < start+size {
+			proof.Siblings = siblings
+		} else {
+			proof.Peaks = append(proof.Peaks, peak)
+		}
+		peaks = append(peaks, peak)
+		start += size
+	}
+
+	root := peaks[len(peaks)-1]
+	for i := len(peaks) - 2; i >= 0; i-- {
+		root = mmrNode(peaks[i], root)
+	}
+	return root, proof
+}
+
+func TestMMRProofVerifyMembership(t *testing.T) {
+	paths := make([]types.MerklePath, 5)
+	leaves := make([][]byte, 5)
+	for i := range leaves {
+		paths[i] = types.NewMerklePath([]string{"mmr", fmt.Sprintf("blocks/%d", i)})
+		leaves[i] = mmrLeaf(paths[i], []byte(fmt.Sprintf("BLOCK%d", i)))
+	}
+
+	// the MMR of 5 leaves has a peak of 4 leaves and a peak of a single leaf:
+	//
+	//         n6
+	//       /    \
+	//     n2      n5
+	//    /  \    /  \
+	//   l0  l1  l2  l3  l4
+	n2, n5 := mmrNode(leaves[0], leaves[1]), mmrNode(leaves[2], leaves[3])
+	n6 := mmrNode(n2, n5)
+	root := types.NewMerkleRoot(mmrNode(n6, leaves[4]))
+
+	cases := []struct {
+		name    string
+		proof   types.MMRProof
+		path    types.MerklePath
+		value   []byte
+		expPass bool
+	}{
+		{"leaf of the first peak", types.NewMMRProof(2, 5, [][]byte{leaves[3], n2}, [][]byte{leaves[4]}), paths[2], []byte("BLOCK2"), true},
+		{"leaf of the last peak", types.NewMMRProof(4, 5, nil, [][]byte{n6}), paths[4], []byte("BLOCK4"), true},
+		{"wrong value", types.NewMMRProof(2, 5, [][]byte{leaves[3], n2}, [][]byte{leaves[4]}), paths[2], []byte("BLOCK3"), false},
+		{"wrong path", types.NewMMRProof(2, 5, [][]byte{leaves[3], n2}, [][]byte{leaves[4]}), paths[3], []byte("BLOCK2"), false},
+		{"wrong leaf index", types.NewMMRProof(3, 5, [][]byte{leaves[3], n2}, [][]byte{leaves[4]}), paths[2], []byte("BLOCK2"), false},
+		{"wrong sibling", types.NewMMRProof(2, 5, [][]byte{leaves[1], n2}, [][]byte{leaves[4]}), paths[2], []byte("BLOCK2"), false},
+		{"wrong peak", types.NewMMRProof(2, 5, [][]byte{leaves[3], n2}, [][]byte{leaves[3]}), paths[2], []byte("BLOCK2"), false},
+		{"missing sibling", types.NewMMRProof(2, 5, [][]byte{leaves[3]}, [][]byte{leaves[4]}), paths[2], []byte("BLOCK2"), false},
+		{"missing peak", types.NewMMRProof(2, 5, [][]byte{leaves[3], n2}, nil), paths[2], []byte("BLOCK2"), false},
+		{"inner node as a leaf", types.NewMMRProof(1, 3, [][]byte{leaves[0]}, [][]byte{leaves[2]}), paths[1], []byte("BLOCK1"), false},
+		{"leaf index out of range", types.NewMMRProof(5, 5, nil, [][]byte{n6}), paths[4], []byte("BLOCK4"), false},
+		{"invalid hash length", types.NewMMRProof(4, 5, nil, [][]byte{n6[:31]}), paths[4], []byte("BLOCK4"), false},
+		{"empty proof", types.MMRProof{}, paths[4], []byte("BLOCK4"), false},
+		{"empty value", types.NewMMRProof(4, 5, nil, [][]byte{n6}), paths[4], nil, false},
+	}
+
+	for i, tc := range cases {
+		err := tc.proof.VerifyMembership(&root, tc.path, tc.value)
+		if tc.expPass {
+			require.NoError(t, err, "test case %d: %s", i, tc.name)
+		} else {
+			require.Error(t, err, "test case %d: %s", i, tc.name)
+		}
+	}
+
+	require.Error(t, types.NewMMRProof(4, 5, nil, [][]byte{n6}).VerifyMembership(nil, paths[4], []byte("BLOCK4")))
+	require.Error(t, types.NewMMRProof(4, 5, nil, [][]byte{n6}).VerifyNonMembership(&root, paths[4]))
+
+	// the proofs of every leaf of MMRs of any size verify
+	for count := 1; count <= 33; count++ {
+		leaves := make([][]byte, count)
+		for i := range leaves {
+			leaves[i] = mmrLeaf(paths[0], []byte(fmt.Sprintf("BLOCK%d", i)))
+		}
+
+		for i := range leaves {
+			rootHash, proof := mmrProve(leaves, i)
+			root := types.NewMerkleRoot(rootHash)
+			require.NoError(t, proof.ValidateBasic(), "leaf %d of %d", i, count)
+			require.NoError(t, proof.VerifyMembership(&root, paths[0], []byte(fmt.Sprintf("BLOCK%d", i))), "leaf %d of %d", i, count)
+		}
+	}
+}
+
+func TestMMRProofValidateBasic(t *testing.T) {
+	hash := make([]byte, sha256.Size)
+
+	// 6 leaves make a peak of 4 leaves and a peak of 2 leaves
+	cases := []struct {
+		name    string
+		proof   types.MMRProof
+		expPass bool
+	}{
+		{"leaf of the first peak", types.NewMMRProof(0, 6, [][]byte{hash, hash}, [][]byte{hash}), true},
+		{"leaf of the last peak", types.NewMMRProof(5, 6, [][]byte{hash}, [][]byte{hash}), true},
+		{"single leaf", types.NewMMRProof(0, 1, nil, nil), true},
+		{"too many siblings", types.NewMMRProof(5, 6, [][]byte{hash, hash}, [][]byte{hash}), false},
+		{"too many peaks", types.NewMMRProof(5, 6, [][]byte{hash}, [][]byte{hash, hash}), false},
+		{"leaf index out of range", types.NewMMRProof(6, 6, [][]byte{hash}, [][]byte{hash}), false},
+		{"empty sibling", types.NewMMRProof(5, 6, [][]byte{nil}, [][]byte{hash}), false},
+		{"no leaves", types.MMRProof{}, false},
+	}
+
+	for i, tc := range cases {
+		err := tc.proof.ValidateBasic()
+		if tc.expPass {
+			require.NoError(t, err, "test case %d: %s", i, tc.name)
+		} else {
+			require.Error(t, err, "test case %d: %s", i, tc.name)
+		}
+	}
+
+	require.True(t, types.MMRProof{}.IsEmpty())
+	require.Equal(t, exported.Merkle, types.MMRProof{}.GetCommitmentType())
+}
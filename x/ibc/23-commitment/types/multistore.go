@@ -0,0 +1,36 @@
+package types
+
+import (
+	"github.com/tendermint/tendermint/crypto/tmhash"
+
+	"github.com/cosmos/cosmos-sdk/store/rootmulti"
+	storetypes "github.com/cosmos/cosmos-sdk/store/types"
+)
+
+// StoreInfo defines the name and the commit ID of one of the substores of a
+// multistore.
+type StoreInfo struct {
+	Name     string
+	CommitID storetypes.CommitID
+}
+
+// NewStoreInfo creates a new StoreInfo instance.
+func NewStoreInfo(name string, commitID storetypes.CommitID) StoreInfo {
+	return StoreInfo{
+		Name:     name,
+		CommitID: commitID,
+	}
+}
+
+// MerkleRootFromStoreInfos computes the root of a multistore from the commit IDs
+// of its substores, using the same simple merkle map as the rootmulti store: each
+// leaf commits to the store name and to the hash of the store commit hash. Store
+// names must be unique.
+func MerkleRootFromStoreInfos(infos []StoreInfo) MerkleRoot {
+	m := make(map[string][]byte, len(infos))
+	for _, info := range infos {
+		m[info.Name] = tmhash.Sum(info.CommitID.Hash)
+	}
+
+	return NewMerkleRoot(rootmulti.SimpleHashFromMap(m))
+}
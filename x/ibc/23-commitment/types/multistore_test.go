@@ -0,0 +1,48 @@
+package types_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	dbm "github.com/tendermint/tm-db"
+
+	"github.com/cosmos/cosmos-sdk/store/iavl"
+	"github.com/cosmos/cosmos-sdk/store/rootmulti"
+	storetypes "github.com/cosmos/cosmos-sdk/store/types"
+	"github.com/cosmos/cosmos-sdk/x/ibc/23-commitment/types"
+)
+
+func TestMerkleRootFromStoreInfos(t *testing.T) {
+	db := dbm.NewMemDB()
+	store := rootmulti.NewStore(db)
+	storeKeys := []*storetypes.KVStoreKey{
+		storetypes.NewKVStoreKey("iavlStoreKey"),
+		storetypes.NewKVStoreKey("otherStoreKey"),
+		storetypes.NewKVStoreKey("emptyStoreKey"),
+	}
+	for _, storeKey := range storeKeys {
+		store.MountStoreWithDB(storeKey, storetypes.StoreTypeIAVL, nil)
+	}
+	require.NoError(t, store.LoadVersion(0))
+
+	store.GetCommitStore(storeKeys[0]).(*iavl.Store).Set([]byte("MYKEY"), []byte("MYVALUE"))
+	store.GetCommitStore(storeKeys[1]).(*iavl.Store).Set([]byte("KEY"), []byte("VALUE"))
+	cid := store.Commit()
+
+	// store infos are sorted by name, so the order they are provided in is irrelevant
+	infos := make([]types.StoreInfo, 0, len(storeKeys))
+	for i := len(storeKeys) - 1; i >= 0; i-- {
+		commitID := store.GetCommitStore(storeKeys[i]).LastCommitID()
+		infos = append(infos, types.NewStoreInfo(storeKeys[i].Name(), commitID))
+	}
+
+	root := types.MerkleRootFromStoreInfos(infos)
+	require.Equal(t, cid.Hash, root.GetHash())
+
+	// a different substore commit produces a different root
+	infos[0].CommitID.Hash = []byte("WRONGHASH")
+	require.NotEqual(t, cid.Hash, types.MerkleRootFromStoreInfos(infos).GetHash())
+
+	// a missing substore produces a different root
+	require.NotEqual(t, cid.Hash, types.MerkleRootFromStoreInfos(infos[1:]).GetHash())
+}
@@ -0,0 +1,39 @@
+package types
+
+import (
+	"errors"
+
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/cosmos/cosmos-sdk/x/ibc/23-commitment/exported"
+)
+
+// VerifyNested verifies the membership of the value at the inner path, against
+// an inner root that is itself committed at the outer path under the outer root,
+// as for recursive light clients. The proofs only commit to the hashes of their
+// values, so the inner root can't be extracted from the outer proof: it is
+// computed from the inner proof, which must prove the value under it, and the
+// outer proof must then prove the inner root as the value of the outer path.
+func VerifyNested(
+	outerProof MerkleProof, outerRoot exported.Root, outerPath exported.Path,
+	innerProof MerkleProof, innerPath exported.Path, value []byte,
+) error {
+	if innerProof.IsEmpty() {
+		return errors.New("empty params or proof")
+	}
+
+	innerHash, err := innerProof.computeRoot()
+	if err != nil {
+		return sdkerrors.Wrap(err, "failed to compute inner root")
+	}
+
+	innerRoot := NewMerkleRoot(innerHash)
+	if err := innerProof.VerifyMembership(&innerRoot, innerPath, value); err != nil {
+		return sdkerrors.Wrap(err, "failed to verify inner proof")
+	}
+
+	if err := outerProof.VerifyMembership(outerRoot, outerPath, innerHash); err != nil {
+		return sdkerrors.Wrapf(err, "failed to verify inner root %X", innerHash)
+	}
+
+	return nil
+}
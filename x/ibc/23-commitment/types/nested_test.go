@@ -0,0 +1,90 @@
+package types_test
+
+import (
+	"fmt"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+	dbm "github.com/tendermint/tm-db"
+
+	"github.com/cosmos/cosmos-sdk/store/rootmulti"
+	storetypes "github.com/cosmos/cosmos-sdk/store/types"
+	"github.com/cosmos/cosmos-sdk/x/ibc/23-commitment/types"
+)
+
+func (suite *MerkleTestSuite) TestVerifyNested() {
+	// the inner chain commits the value
+	innerStore := rootmulti.NewStore(dbm.NewMemDB())
+	innerKey := storetypes.NewKVStoreKey("innerStoreKey")
+	innerStore.MountStoreWithDB(innerKey, storetypes.StoreTypeIAVL, nil)
+	suite.Require().NoError(innerStore.LoadVersion(0))
+
+	innerStore.GetCommitKVStore(innerKey).Set([]byte("MYKEY"), []byte("MYVALUE"))
+	innerCid := innerStore.Commit()
+
+	res := innerStore.Query(abci.RequestQuery{
+		Path:   fmt.Sprintf("/%s/key", innerKey.Name()), // required path to get key/value+proof
+		Data:   []byte("MYKEY"),
+		Height: innerCid.Version,
+		Prove:  true,
+	})
+	suite.Require().NotNil(res.Proof)
+	innerProof := types.MerkleProof{Proof: res.Proof}
+	innerPath := types.NewMerklePath([]string{innerKey.Name(), "MYKEY"})
+
+	// the outer chain commits the root of the inner chain
+	suite.iavlStore.Set([]byte("INNERROOT"), innerCid.Hash)
+	suite.iavlStore.Set([]byte("OTHERROOT"), []byte("OTHERROOT"))
+	cid := suite.store.Commit()
+
+	query := func(key string) types.MerkleProof {
+		res := suite.store.Query(abci.RequestQuery{
+			Path:   fmt.Sprintf("/%s/key", suite.storeKey.Name()), // required path to get key/value+proof
+			Data:   []byte(key),
+			Height: cid.Version,
+			Prove:  true,
+		})
+		suite.Require().NotNil(res.Proof)
+		return types.MerkleProof{Proof: res.Proof}
+	}
+
+	outerProof := query("INNERROOT")
+	outerRoot := types.NewMerkleRoot(cid.Hash)
+	outerPath := types.NewMerklePath([]string{suite.storeKey.Name(), "INNERROOT"})
+	otherPath := types.NewMerklePath([]string{suite.storeKey.Name(), "OTHERROOT"})
+	wrongRoot := types.NewMerkleRoot(innerCid.Hash)
+
+	cases := []struct {
+		name       string
+		outerProof types.MerkleProof
+		outerRoot  types.MerkleRoot
+		outerPath  types.MerklePath
+		innerProof types.MerkleProof
+		innerPath  types.MerklePath
+		value      []byte
+		expPass    bool
+	}{
+		{"two-layer nesting", outerProof, outerRoot, outerPath, innerProof, innerPath, []byte("MYVALUE"), true},
+		{"wrong value", outerProof, outerRoot, outerPath, innerProof, innerPath, []byte("WRONGVALUE"), false},
+		{"wrong inner path", outerProof, outerRoot, outerPath, innerProof, types.NewMerklePath([]string{innerKey.Name(), "OTHERKEY"}), []byte("MYVALUE"), false},
+		{"inner root not committed at outer path", query("OTHERROOT"), outerRoot, otherPath, innerProof, innerPath, []byte("MYVALUE"), false},
+		{"wrong outer root", outerProof, wrongRoot, outerPath, innerProof, innerPath, []byte("MYVALUE"), false},
+		{"inner proof as outer proof", innerProof, outerRoot, outerPath, innerProof, innerPath, []byte("MYVALUE"), false},
+		{"empty inner proof", outerProof, outerRoot, outerPath, types.MerkleProof{}, innerPath, []byte("MYVALUE"), false},
+		{"empty outer proof", types.MerkleProof{}, outerRoot, outerPath, innerProof, innerPath, []byte("MYVALUE"), false},
+	}
+
+	for i, tc := range cases {
+		tc := tc
+		suite.Run(tc.name, func() {
+			err := types.VerifyNested(tc.outerProof, &tc.outerRoot, tc.outerPath, tc.innerProof, tc.innerPath, tc.value)
+
+			if tc.expPass {
+				// nolint: scopelint
+				suite.Require().NoError(err, "test case %d should have passed", i)
+			} else {
+				// nolint: scopelint
+				suite.Require().Error(err, "test case %d should have failed", i)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package types
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/tendermint/iavl"
+	"github.com/tendermint/tendermint/crypto/merkle"
+
+	"github.com/cosmos/cosmos-sdk/store/rootmulti"
+)
+
+// operatorFacts defines the facts proven by a single proof operation: the key it
+// consumes, the subroot it computes and the leaves it commits to.
+type operatorFacts struct {
+	opType  string
+	key     []byte
+	subroot []byte
+	leaves  [][]byte
+}
+
+// equal returns true if both operations prove the same facts.
+func (f operatorFacts) equal(other operatorFacts) bool {
+	if f.opType != other.opType || !bytes.Equal(f.key, other.key) ||
+		!bytes.Equal(f.subroot, other.subroot) || len(f.leaves) != len(other.leaves) {
+		return false
+	}
+
+	for i := range f.leaves {
+		if !bytes.Equal(f.leaves[i], other.leaves[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// getOperatorFacts computes the facts proven by the operations supported by the
+// default multistore proof runtime.
+func getOperatorFacts(op merkle.ProofOperator) (operatorFacts, error) {
+	facts := operatorFacts{
+		opType: op.ProofOp().Type,
+		key:    op.GetKey(),
+	}
+
+	switch op := op.(type) {
+	case iavl.ValueOp:
+		facts.subroot, facts.leaves = iavlFacts(op.Proof)
+	case iavl.AbsenceOp:
+		facts.subroot, facts.leaves = iavlFacts(op.Proof)
+	case rootmulti.MultiStoreProofOp:
+		if op.Proof == nil {
+			return operatorFacts{}, fmt.Errorf("empty multistore proof for key %s", op.GetKey())
+		}
+		facts.subroot = op.Proof.ComputeRootHash()
+	case merkle.SimpleValueOp:
+		if op.Proof == nil {
+			return operatorFacts{}, fmt.Errorf("empty simple value proof for key %s", op.GetKey())
+		}
+		facts.subroot = op.Proof.ComputeRootHash()
+		facts.leaves = [][]byte{op.Proof.LeafHash}
+	default:
+		return operatorFacts{}, fmt.Errorf("unsupported proof operation type %s", facts.opType)
+	}
+
+	return facts, nil
+}
+
+// iavlFacts returns the root and the key and value hash of each leaf of an IAVL
+// range proof. A nil proof corresponds to an empty tree.
+func iavlFacts(proof *iavl.RangeProof) ([]byte, [][]byte) {
+	if proof == nil {
+		return nil, nil
+	}
+
+	leaves := make([][]byte, len(proof.Leaves))
+	for i, leaf := range proof.Leaves {
+		leaves[i] = append(append([]byte{}, leaf.Key...), leaf.ValueHash...)
+	}
+
+	return proof.ComputeRootHash(), leaves
+}
+
+// iavlTreeSize returns the number of leaves of the tree the range proof was
+// built from, as recorded by the root node of its left path.
+func iavlTreeSize(proof *iavl.RangeProof) int64 {
+	if len(proof.LeftPath) == 0 {
+		return 1
+	}
+	return proof.LeftPath[0].Size
+}
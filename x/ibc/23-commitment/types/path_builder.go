@@ -0,0 +1,57 @@
+package types
+
+import (
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	host "github.com/cosmos/cosmos-sdk/x/ibc/24-host"
+)
+
+// PathBuilder assembles a MerklePath from the name of the store that commits
+// the key, an optional key prefix within that store and an ICS24 key path.
+//
+// Usage:
+//
+//	path, err := NewPathBuilder().WithStore("ibc").WithKey("connections/connectionidone").Build()
+type PathBuilder struct {
+	store  string
+	prefix []byte
+	key    string
+}
+
+// NewPathBuilder creates a new empty PathBuilder instance
+func NewPathBuilder() PathBuilder {
+	return PathBuilder{}
+}
+
+// WithStore sets the name of the store under which the key is committed. This
+// is the first key of the resulting path.
+func (pb PathBuilder) WithStore(name string) PathBuilder {
+	pb.store = name
+	return pb
+}
+
+// WithPrefix sets the prefix that is prepended to the key within the store.
+func (pb PathBuilder) WithPrefix(prefix MerklePrefix) PathBuilder {
+	pb.prefix = prefix.Bytes()
+	return pb
+}
+
+// WithKey sets the ICS24 path of the key committed within the store.
+func (pb PathBuilder) WithKey(key string) PathBuilder {
+	pb.key = key
+	return pb
+}
+
+// Build validates the builder fields and returns the MerklePath
+// [store, prefix + key].
+func (pb PathBuilder) Build() (MerklePath, error) {
+	if pb.store == "" {
+		return MerklePath{}, sdkerrors.Wrap(ErrInvalidPrefix, "store name cannot be empty")
+	}
+
+	if err := host.PathValidator(pb.key); err != nil {
+		return MerklePath{}, sdkerrors.Wrapf(err, "invalid key path %s", pb.key)
+	}
+
+	key := append(append([]byte{}, pb.prefix...), pb.key...)
+	return NewMerklePath([]string{pb.store, string(key)}), nil
+}
@@ -0,0 +1,71 @@
+package types_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cosmos/cosmos-sdk/x/ibc/23-commitment/types"
+	host "github.com/cosmos/cosmos-sdk/x/ibc/24-host"
+)
+
+func TestPathBuilder(t *testing.T) {
+	connectionPath := host.ConnectionPath("connectionidone")
+	prefixedPath, err := types.ApplyPrefix(types.NewMerklePrefix([]byte("ibc")), connectionPath)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name    string
+		builder types.PathBuilder
+		expPath types.MerklePath
+		expPass bool
+	}{
+		{
+			"store and key",
+			types.NewPathBuilder().WithStore("ibc").WithKey(connectionPath),
+			prefixedPath,
+			true,
+		},
+		{
+			"order of calls doesn't matter",
+			types.NewPathBuilder().WithKey(connectionPath).WithStore("ibc"),
+			prefixedPath,
+			true,
+		},
+		{
+			"key prefix within the store",
+			types.NewPathBuilder().WithStore("ibc").WithPrefix(types.NewMerklePrefix([]byte("0/"))).WithKey(connectionPath),
+			types.NewMerklePath([]string{"ibc", "0/" + connectionPath}),
+			true,
+		},
+		{
+			"empty store",
+			types.NewPathBuilder().WithKey(connectionPath),
+			types.MerklePath{},
+			false,
+		},
+		{
+			"empty key",
+			types.NewPathBuilder().WithStore("ibc"),
+			types.MerklePath{},
+			false,
+		},
+		{
+			"invalid key",
+			types.NewPathBuilder().WithStore("ibc").WithKey("invalid-path/doesitfail?/hopefully"),
+			types.MerklePath{},
+			false,
+		},
+	}
+
+	for _, tc := range testCases {
+		path, err := tc.builder.Build()
+		if tc.expPass {
+			require.NoError(t, err, tc.name)
+			require.Equal(t, tc.expPath.String(), path.String(), tc.name)
+		} else {
+			require.Error(t, err, tc.name)
+			require.Equal(t, tc.expPath, path, tc.name)
+		}
+	}
+}
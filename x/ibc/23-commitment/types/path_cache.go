@@ -0,0 +1,70 @@
+package types
+
+import (
+	"sync"
+
+	"github.com/cosmos/cosmos-sdk/x/ibc/23-commitment/exported"
+)
+
+// pathCacheKey defines the key of a path memoized by a PathCache.
+type pathCacheKey struct {
+	prefix string
+	path   string
+}
+
+// PathCache memoizes the commitment paths constructed by ApplyPrefix, keyed by
+// prefix and raw path, so that verifying many values under the same prefix
+// doesn't validate and construct the same path repeatedly. Failed applications
+// are not cached. The zero value is an empty cache, safe for concurrent use.
+type PathCache struct {
+	mtx   sync.RWMutex
+	paths map[pathCacheKey]MerklePath
+}
+
+// Apply returns the commitment path of the given path in the context of the
+// given prefix, as ApplyPrefix, from the cache if it has already been applied.
+func (pc *PathCache) Apply(prefix exported.Prefix, path string) (exported.Path, error) {
+	if prefix == nil || prefix.IsEmpty() {
+		return ApplyPrefix(prefix, path)
+	}
+
+	key := pathCacheKey{prefix: string(prefix.Bytes()), path: path}
+
+	pc.mtx.RLock()
+	merklePath, ok := pc.paths[key]
+	pc.mtx.RUnlock()
+	if ok {
+		return copyMerklePath(merklePath), nil
+	}
+
+	merklePath, err := ApplyPrefix(prefix, path)
+	if err != nil {
+		return nil, err
+	}
+
+	pc.mtx.Lock()
+	if pc.paths == nil {
+		pc.paths = make(map[pathCacheKey]MerklePath)
+	}
+	pc.paths[key] = merklePath
+	pc.mtx.Unlock()
+
+	return copyMerklePath(merklePath), nil
+}
+
+// Len returns the number of paths in the cache.
+func (pc *PathCache) Len() int {
+	pc.mtx.RLock()
+	defer pc.mtx.RUnlock()
+	return len(pc.paths)
+}
+
+// copyMerklePath returns a deep copy of the path, so the cached paths can't be
+// modified by the callers.
+func copyMerklePath(mp MerklePath) MerklePath {
+	keys := make([]*Key, len(mp.KeyPath.Keys))
+	for i, key := range mp.KeyPath.Keys {
+		keys[i] = &Key{name: append([]byte(nil), key.name...), enc: key.enc}
+	}
+	return MerklePath{KeyPath: KeyPath{Keys: keys}}
+}
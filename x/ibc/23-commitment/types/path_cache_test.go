@@ -0,0 +1,83 @@
+package types_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cosmos/cosmos-sdk/x/ibc/23-commitment/types"
+)
+
+func TestPathCache(t *testing.T) {
+	var cache types.PathCache
+
+	prefix := types.NewMerklePrefix([]byte("ibc"))
+	otherPrefix := types.NewMerklePrefix([]byte("other"))
+	path := "ports/transfer/channels/channel-0/sequences/1"
+
+	expPath, err := types.ApplyPrefix(&prefix, path)
+	require.NoError(t, err)
+
+	// a miss applies the prefix and a hit returns an identical path
+	miss, err := cache.Apply(&prefix, path)
+	require.NoError(t, err)
+	require.Equal(t, expPath, miss)
+	require.Equal(t, 1, cache.Len())
+
+	hit, err := cache.Apply(&prefix, path)
+	require.NoError(t, err)
+	require.Equal(t, expPath, hit)
+	require.Equal(t, miss.String(), hit.String())
+	require.Equal(t, 1, cache.Len())
+
+	// modifying a returned path doesn't modify the cached one
+	modified := hit.(types.MerklePath)
+	modified.KeyPath = modified.KeyPath.AppendKey([]byte("extra"), types.URL)
+	hit, err = cache.Apply(&prefix, path)
+	require.NoError(t, err)
+	require.Equal(t, expPath, hit)
+
+	// paths are keyed by both prefix and raw path
+	otherPath, err := cache.Apply(&otherPrefix, path)
+	require.NoError(t, err)
+	expOtherPath, err := types.ApplyPrefix(&otherPrefix, path)
+	require.NoError(t, err)
+	require.Equal(t, expOtherPath, otherPath)
+	require.NotEqual(t, hit, otherPath)
+	require.Equal(t, 2, cache.Len())
+
+	// failed applications are not cached
+	emptyPrefix := types.NewMerklePrefix(nil)
+	_, err = cache.Apply(&emptyPrefix, path)
+	require.Error(t, err)
+	_, err = cache.Apply(nil, path)
+	require.Error(t, err)
+	_, err = cache.Apply(&prefix, "(invalid)/path")
+	require.Error(t, err)
+	require.Equal(t, 2, cache.Len())
+}
+
+func TestPathCacheConcurrent(t *testing.T) {
+	var (
+		cache types.PathCache
+		wg    sync.WaitGroup
+	)
+
+	prefix := types.NewMerklePrefix([]byte("ibc"))
+	expPath, err := types.ApplyPrefix(&prefix, "clients/ethbridge/clientState")
+	require.NoError(t, err)
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			path, err := cache.Apply(&prefix, "clients/ethbridge/clientState")
+			require.NoError(t, err)
+			require.Equal(t, expPath, path)
+		}()
+	}
+	wg.Wait()
+
+	require.Equal(t, 1, cache.Len())
+}
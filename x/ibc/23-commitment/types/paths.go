@@ -0,0 +1,114 @@
+package types
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	host "github.com/cosmos/cosmos-sdk/x/ibc/24-host"
+)
+
+// ClientStatePath returns the commitment path under which the IBC store commits
+// the state of the given client: ibc/clients/{clientID}/clientState.
+func ClientStatePath(clientID string) MerklePath {
+	return NewMerklePath([]string{host.StoreKey, clientKey(clientID, host.ClientStatePath())})
+}
+
+// ConsensusStatePath returns the commitment path under which the IBC store
+// commits the consensus state of the given client at the given height:
+// ibc/clients/{clientID}/consensusState/{height}, with the height formatted in
+// decimal without padding.
+func ConsensusStatePath(clientID string, height uint64) MerklePath {
+	return NewMerklePath([]string{host.StoreKey, clientKey(clientID, host.ConsensusStatePath(height))})
+}
+
+// ConnectionPath returns the commitment path under which the IBC store commits
+// the end of the given connection: ibc/connections/{connectionID}.
+func ConnectionPath(connectionID string) MerklePath {
+	return NewMerklePath([]string{host.StoreKey, host.ConnectionPath(connectionID)})
+}
+
+// PacketCommitmentPath returns the commitment path under which the IBC store
+// commits the packet commitment of the given sequence on the given channel:
+// ibc/commitments/ports/{portID}/channels/{channelID}/packets/{sequence}, with
+// the sequence formatted in decimal, as the channel keeper commits it.
+func PacketCommitmentPath(portID, channelID string, sequence uint64) MerklePath {
+	return NewMerklePath([]string{host.StoreKey, host.PacketCommitmentPath(portID, channelID, sequence)})
+}
+
+// PathFromKey reconstructs the commitment path of a committed key, as
+// ApplyPrefix builds it, from the full key bytes: the prefix followed by the key
+// within the prefixed store. It returns an empty path if the key doesn't start
+// with the prefix or has no key after it.
+func PathFromKey(prefix MerklePrefix, key []byte) MerklePath {
+	if prefix.IsEmpty() || len(key) <= len(prefix.Bytes()) || !bytes.HasPrefix(key, prefix.Bytes()) {
+		return MerklePath{}
+	}
+
+	return NewMerklePath([]string{string(prefix.Bytes()), string(key[len(prefix.Bytes()):])})
+}
+
+// PortChannel returns the port and channel identifiers of a packet path, whose
+// last key follows the commitments/ports/{portID}/channels/{channelID}/... layout
+// of the IBC store, eg the paths built by PacketCommitmentPath.
+func (mp MerklePath) PortChannel() (portID, channelID string, err error) {
+	segments := mp.Segments()
+	if len(segments) == 0 {
+		return "", "", fmt.Errorf("cannot parse port and channel of empty path")
+	}
+
+	key := segments[len(segments)-1]
+	split := strings.Split(key, "/")
+	if len(split) < 6 || split[0] != host.KeyPacketCommitmentPrefix || split[1] != "ports" || split[3] != "channels" {
+		return "", "", fmt.Errorf("cannot parse port and channel of path %s", key)
+	}
+
+	portID, channelID = split[2], split[4]
+	if err := host.PortIdentifierValidator(portID); err != nil {
+		return "", "", fmt.Errorf("invalid port identifier of path %s: %w", key, err)
+	}
+	if err := host.ChannelIdentifierValidator(channelID); err != nil {
+		return "", "", fmt.Errorf("invalid channel identifier of path %s: %w", key, err)
+	}
+
+	return portID, channelID, nil
+}
+
+// ExtractHeight returns the height of a consensus state path, whose last key ends
+// with a consensusState/{height} segment, as built by ConsensusStatePath, or with
+// the consensusStates/{height} segment of the newer layout. The height may also
+// be in the {revision}-{height} format, in which case the height within the
+// revision is returned.
+func (mp MerklePath) ExtractHeight() (uint64, error) {
+	segments := mp.Segments()
+	if len(segments) == 0 {
+		return 0, fmt.Errorf("cannot extract height of empty path")
+	}
+
+	key := segments[len(segments)-1]
+	split := strings.Split(key, "/")
+	if len(split) < 2 || (split[len(split)-2] != "consensusState" && split[len(split)-2] != "consensusStates") {
+		return 0, fmt.Errorf("path %s is not a consensus state path", key)
+	}
+
+	heightStr := split[len(split)-1]
+	if i := strings.Index(heightStr, "-"); i != -1 {
+		if _, err := strconv.ParseUint(heightStr[:i], 10, 64); err != nil {
+			return 0, fmt.Errorf("invalid revision of path %s: %w", key, err)
+		}
+		heightStr = heightStr[i+1:]
+	}
+
+	height, err := strconv.ParseUint(heightStr, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid height of path %s: %w", key, err)
+	}
+
+	return height, nil
+}
+
+// clientKey returns the key of the given path within the store of a client.
+func clientKey(clientID, path string) string {
+	return fmt.Sprintf("%s/%s/%s", host.KeyClientStorePrefix, clientID, path)
+}
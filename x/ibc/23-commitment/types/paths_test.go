@@ -0,0 +1,261 @@
+package types_test
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/tendermint/abci/types"
+	dbm "github.com/tendermint/tm-db"
+
+	"github.com/cosmos/cosmos-sdk/store/prefix"
+	"github.com/cosmos/cosmos-sdk/store/rootmulti"
+	storetypes "github.com/cosmos/cosmos-sdk/store/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/ibc/23-commitment/types"
+	host "github.com/cosmos/cosmos-sdk/x/ibc/24-host"
+)
+
+const testClientID = "ethbridge"
+
+// ibcStore mounts an IAVL store under the IBC store key
+func ibcStore(t *testing.T) (*rootmulti.Store, *storetypes.KVStoreKey) {
+	store := rootmulti.NewStore(dbm.NewMemDB())
+	storeKey := storetypes.NewKVStoreKey(host.StoreKey)
+	store.MountStoreWithDB(storeKey, storetypes.StoreTypeIAVL, nil)
+	require.NoError(t, store.LoadVersion(0))
+	return store, storeKey
+}
+
+// queryIBCProof queries a proof of the given key on the IBC store at the latest
+// committed version
+func queryIBCProof(t *testing.T, store *rootmulti.Store, key []byte) types.MerkleProof {
+	res := store.Query(abci.RequestQuery{
+		Path:   fmt.Sprintf("/%s/key", host.StoreKey), // required path to get key/value+proof
+		Data:   key,
+		Height: store.LastCommitID().Version,
+		Prove:  true,
+	})
+	require.NotNil(t, res.Proof)
+	return types.MerkleProof{Proof: res.Proof}
+}
+
+func TestClientStatePath(t *testing.T) {
+	store, storeKey := ibcStore(t)
+
+	// commit the client states the same way the client keeper stores them
+	clientIDs := []string{testClientID, "ibconeclient", "ibconeclient2"}
+	for _, clientID := range clientIDs {
+		clientStore := prefix.NewStore(store.GetCommitKVStore(storeKey), append([]byte("clients/"+clientID), '/'))
+		clientStore.Set(host.KeyClientState(), []byte("client state "+clientID))
+	}
+	cid := store.Commit()
+	root := types.NewMerkleRoot(cid.Hash)
+
+	for i, clientID := range clientIDs {
+		path := types.ClientStatePath(clientID)
+		require.Equal(t, fmt.Sprintf("/ibc/clients/%s/clientState", clientID), path.Pretty())
+
+		proof := queryIBCProof(t, store, []byte(fmt.Sprintf("clients/%s/clientState", clientID)))
+		require.NoError(t, proof.VerifyMembership(&root, path, []byte("client state "+clientID)), clientID)
+
+		// the path of another client must not verify the client state
+		other := clientIDs[(i+1)%len(clientIDs)]
+		require.Error(t, proof.VerifyMembership(&root, types.ClientStatePath(other), []byte("client state "+clientID)), clientID)
+	}
+}
+
+func TestConnectionPath(t *testing.T) {
+	store, storeKey := ibcStore(t)
+
+	// commit the connections the same way the connection keeper stores them
+	connectionIDs := []string{"connectionidone", "connectionidtwo", "connection-0"}
+	for _, connectionID := range connectionIDs {
+		store.GetCommitKVStore(storeKey).Set(host.KeyConnection(connectionID), []byte("connection "+connectionID))
+	}
+	cid := store.Commit()
+	root := types.NewMerkleRoot(cid.Hash)
+
+	for i, connectionID := range connectionIDs {
+		path := types.ConnectionPath(connectionID)
+		require.Equal(t, fmt.Sprintf("/ibc/connections/%s", connectionID), path.Pretty())
+
+		proof := queryIBCProof(t, store, host.KeyConnection(connectionID))
+		require.NoError(t, proof.VerifyMembership(&root, path, []byte("connection "+connectionID)), connectionID)
+
+		// the path of another connection must not verify the connection
+		other := connectionIDs[(i+1)%len(connectionIDs)]
+		require.Error(t, proof.VerifyMembership(&root, types.ConnectionPath(other), []byte("connection "+connectionID)), connectionID)
+	}
+}
+
+func TestConsensusStatePath(t *testing.T) {
+	store, storeKey := ibcStore(t)
+
+	// commit the consensus states the same way the client keeper stores them
+	heights := []uint64{0, 1, 9, 10, 99, 100, 12345, math.MaxUint64}
+	clientStore := prefix.NewStore(store.GetCommitKVStore(storeKey), append([]byte("clients/"+testClientID), '/'))
+	for _, height := range heights {
+		clientStore.Set(host.KeyConsensusState(height), []byte(fmt.Sprintf("consensus state %d", height)))
+	}
+	cid := store.Commit()
+	root := types.NewMerkleRoot(cid.Hash)
+
+	for _, height := range heights {
+		path := types.ConsensusStatePath(testClientID, height)
+		require.Equal(t, fmt.Sprintf("/ibc/clients/%s/consensusState/%d", testClientID, height), path.Pretty())
+
+		key := []byte(fmt.Sprintf("clients/%s/consensusState/%d", testClientID, height))
+		proof := queryIBCProof(t, store, key)
+		require.NoError(t, proof.VerifyMembership(&root, path, []byte(fmt.Sprintf("consensus state %d", height))), height)
+
+		// the path of another height must not verify the consensus state
+		require.Error(t, proof.VerifyMembership(&root, types.ConsensusStatePath(testClientID, height+1), []byte(fmt.Sprintf("consensus state %d", height))), height)
+	}
+
+	// a zero padded height isn't the committed key
+	padded := types.NewMerklePath([]string{host.StoreKey, fmt.Sprintf("clients/%s/consensusState/%020d", testClientID, 10)})
+	proof := queryIBCProof(t, store, []byte(fmt.Sprintf("clients/%s/consensusState/10", testClientID)))
+	require.Error(t, proof.VerifyMembership(&root, padded, []byte("consensus state 10")))
+}
+
+func TestPacketCommitmentPath(t *testing.T) {
+	store, storeKey := ibcStore(t)
+
+	// commit the packet commitments the same way the channel keeper stores them
+	sequences := []uint64{0, 1, 9, 10, 255, 256, 1 << 32, math.MaxUint64}
+	for _, sequence := range sequences {
+		key := host.KeyPacketCommitment("transfer", "channel-0", sequence)
+		store.GetCommitKVStore(storeKey).Set(key, []byte(fmt.Sprintf("commitment %d", sequence)))
+	}
+	cid := store.Commit()
+	root := types.NewMerkleRoot(cid.Hash)
+
+	for _, sequence := range sequences {
+		path := types.PacketCommitmentPath("transfer", "channel-0", sequence)
+		require.Equal(t, fmt.Sprintf("/ibc/commitments/ports/transfer/channels/channel-0/packets/%d", sequence), path.Pretty())
+
+		proof := queryIBCProof(t, store, host.KeyPacketCommitment("transfer", "channel-0", sequence))
+		require.NoError(t, proof.VerifyMembership(&root, path, []byte(fmt.Sprintf("commitment %d", sequence))), sequence)
+
+		// the path of another sequence or channel must not verify the commitment
+		require.Error(t, proof.VerifyMembership(&root, types.PacketCommitmentPath("transfer", "channel-0", sequence+1), []byte(fmt.Sprintf("commitment %d", sequence))), sequence)
+		require.Error(t, proof.VerifyMembership(&root, types.PacketCommitmentPath("transfer", "channel-1", sequence), []byte(fmt.Sprintf("commitment %d", sequence))), sequence)
+	}
+
+	// a big endian sequence isn't the committed key
+	bigEndian := types.NewMerklePath([]string{host.StoreKey, "commitments/ports/transfer/channels/channel-0/packets/" + string(sdk.Uint64ToBigEndian(10))})
+	proof := queryIBCProof(t, store, host.KeyPacketCommitment("transfer", "channel-0", 10))
+	require.Error(t, proof.VerifyMembership(&root, bigEndian, []byte("commitment 10")))
+}
+
+func TestPathFromKey(t *testing.T) {
+	store, storeKey := ibcStore(t)
+
+	// commit the client state the same way the client keeper stores it
+	clientStore := prefix.NewStore(store.GetCommitKVStore(storeKey), append([]byte("clients/"+testClientID), '/'))
+	clientStore.Set(host.KeyClientState(), []byte("client state"))
+	cid := store.Commit()
+	root := types.NewMerkleRoot(cid.Hash)
+
+	storeKeyBz := []byte(fmt.Sprintf("clients/%s/clientState", testClientID))
+	proof := queryIBCProof(t, store, storeKeyBz)
+
+	merklePrefix := types.NewMerklePrefix([]byte(host.StoreKey))
+	path := types.PathFromKey(merklePrefix, append([]byte(host.StoreKey), storeKeyBz...))
+
+	expPath, err := types.ApplyPrefix(merklePrefix, string(storeKeyBz))
+	require.NoError(t, err)
+	require.Equal(t, expPath, path)
+	require.Equal(t, []string{host.StoreKey, string(storeKeyBz)}, path.Segments())
+	require.NoError(t, proof.VerifyMembership(&root, path, []byte("client state")))
+
+	testCases := []struct {
+		name   string
+		prefix types.MerklePrefix
+		key    []byte
+	}{
+		{"key without prefix", merklePrefix, storeKeyBz},
+		{"prefix only", merklePrefix, []byte(host.StoreKey)},
+		{"empty key", merklePrefix, nil},
+		{"empty prefix", types.NewMerklePrefix(nil), storeKeyBz},
+	}
+
+	for _, tc := range testCases {
+		require.True(t, types.PathFromKey(tc.prefix, tc.key).IsEmpty(), tc.name)
+	}
+}
+
+func TestPortChannel(t *testing.T) {
+	prefix := types.NewMerklePrefix([]byte(host.StoreKey))
+	applied, err := types.ApplyPrefix(&prefix, "commitments/ports/transfer/channels/channelzero/packets/1")
+	require.NoError(t, err)
+
+	cases := []struct {
+		name         string
+		path         types.MerklePath
+		expPortID    string
+		expChannelID string
+		expPass      bool
+	}{
+		{"packet commitment path", types.PacketCommitmentPath("transfer", "channelzero", 1), "transfer", "channelzero", true},
+		{"prefixed packet path", applied, "transfer", "channelzero", true},
+		{"unprefixed packet path", types.NewMerklePath([]string{"commitments/ports/bank/channels/channelone/packets/10"}), "bank", "channelone", true},
+		{"acknowledgement path", types.NewMerklePath([]string{host.StoreKey, host.PacketAcknowledgementPath("transfer", "channelzero", 1)}), "", "", false},
+		{"channel path", types.NewMerklePath([]string{host.StoreKey, host.ChannelPath("transfer", "channelzero")}), "", "", false},
+		{"missing packet suffix", types.NewMerklePath([]string{host.StoreKey, "commitments/ports/transfer/channels/channelzero"}), "", "", false},
+		{"misplaced channels segment", types.NewMerklePath([]string{host.StoreKey, "commitments/ports/transfer/channel/channelzero/packets/1"}), "", "", false},
+		{"invalid port identifier", types.NewMerklePath([]string{host.StoreKey, "commitments/ports/(transfer)/channels/channelzero/packets/1"}), "", "", false},
+		{"empty channel identifier", types.NewMerklePath([]string{host.StoreKey, "commitments/ports/transfer/channels//packets/1"}), "", "", false},
+		{"empty path", types.MerklePath{}, "", "", false},
+	}
+
+	for _, tc := range cases {
+		portID, channelID, err := tc.path.PortChannel()
+
+		if tc.expPass {
+			require.NoError(t, err, tc.name)
+		} else {
+			require.Error(t, err, tc.name)
+		}
+		require.Equal(t, tc.expPortID, portID, tc.name)
+		require.Equal(t, tc.expChannelID, channelID, tc.name)
+	}
+}
+
+func TestExtractHeight(t *testing.T) {
+	cases := []struct {
+		name      string
+		path      types.MerklePath
+		expHeight uint64
+		expPass   bool
+	}{
+		{"consensus state path", types.ConsensusStatePath(testClientID, 10), 10, true},
+		{"max height", types.ConsensusStatePath(testClientID, math.MaxUint64), math.MaxUint64, true},
+		{"newer layout", types.NewMerklePath([]string{host.StoreKey, "clients/ethbridge/consensusStates/42"}), 42, true},
+		{"revision format", types.NewMerklePath([]string{host.StoreKey, "clients/ethbridge/consensusStates/1-42"}), 42, true},
+		{"revision format in older layout", types.NewMerklePath([]string{host.StoreKey, "clients/ethbridge/consensusState/2-7"}), 7, true},
+		{"unprefixed path", types.NewMerklePath([]string{"consensusState/5"}), 5, true},
+		{"client state path", types.ClientStatePath(testClientID), 0, false},
+		{"missing height", types.NewMerklePath([]string{host.StoreKey, "clients/ethbridge/consensusState/"}), 0, false},
+		{"non numeric height", types.NewMerklePath([]string{host.StoreKey, "clients/ethbridge/consensusState/ten"}), 0, false},
+		{"negative height", types.NewMerklePath([]string{host.StoreKey, "clients/ethbridge/consensusState/-1"}), 0, false},
+		{"non numeric revision", types.NewMerklePath([]string{host.StoreKey, "clients/ethbridge/consensusStates/a-1"}), 0, false},
+		{"missing revision height", types.NewMerklePath([]string{host.StoreKey, "clients/ethbridge/consensusStates/1-"}), 0, false},
+		{"height overflow", types.NewMerklePath([]string{host.StoreKey, "clients/ethbridge/consensusState/18446744073709551616"}), 0, false},
+		{"trailing segment", types.NewMerklePath([]string{host.StoreKey, "clients/ethbridge/consensusState/10/extra"}), 0, false},
+		{"empty path", types.MerklePath{}, 0, false},
+	}
+
+	for _, tc := range cases {
+		height, err := tc.path.ExtractHeight()
+
+		if tc.expPass {
+			require.NoError(t, err, tc.name)
+		} else {
+			require.Error(t, err, tc.name)
+		}
+		require.Equal(t, tc.expHeight, height, tc.name)
+	}
+}
@@ -0,0 +1,51 @@
+package types
+
+import (
+	"sync"
+)
+
+// PrettyCache memoizes the unescaped form of merkle paths, so that rendering the
+// same path repeatedly (eg: in relayer logs) doesn't unescape it each time. The
+// zero value is an empty cache, safe for concurrent use.
+type PrettyCache struct {
+	mtx   sync.RWMutex
+	paths map[string]string // escaped path -> unescaped path
+}
+
+// NewPrettyCache creates a new, empty PrettyCache.
+func NewPrettyCache() *PrettyCache {
+	return &PrettyCache{
+		paths: make(map[string]string),
+	}
+}
+
+// Pretty returns the unescaped path, as MerklePath.Pretty, computing and caching
+// it on the first call for the given path.
+func (pc *PrettyCache) Pretty(mp MerklePath) string {
+	key := mp.String()
+
+	pc.mtx.RLock()
+	pretty, ok := pc.paths[key]
+	pc.mtx.RUnlock()
+	if ok {
+		return pretty
+	}
+
+	pretty = mp.Pretty()
+
+	pc.mtx.Lock()
+	if pc.paths == nil {
+		pc.paths = make(map[string]string)
+	}
+	pc.paths[key] = pretty
+	pc.mtx.Unlock()
+
+	return pretty
+}
+
+// Len returns the number of cached paths.
+func (pc *PrettyCache) Len() int {
+	pc.mtx.RLock()
+	defer pc.mtx.RUnlock()
+	return len(pc.paths)
+}
@@ -0,0 +1,65 @@
+package types_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cosmos/cosmos-sdk/x/ibc/23-commitment/types"
+)
+
+var prettyPaths = [][]string{
+	{"ibc", "commitments/ports/transfer/channels/channel-0/packets/1"},
+	{"ibc", "connections/connection-0"},
+	{"ibc", "key with spaces & symbols?"},
+	{"acc", "\x03\xff"},
+}
+
+func TestPrettyCache(t *testing.T) {
+	cache := types.NewPrettyCache()
+
+	for i := 0; i < 2; i++ {
+		for _, keys := range prettyPaths {
+			path := types.NewMerklePath(keys)
+			require.Equal(t, path.Pretty(), cache.Pretty(path))
+		}
+	}
+	require.Equal(t, len(prettyPaths), cache.Len())
+
+	// concurrent reads and writes on the zero value
+	cache = &types.PrettyCache{}
+	require.Zero(t, cache.Len())
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for _, keys := range prettyPaths {
+				path := types.NewMerklePath(keys)
+				require.Equal(t, path.Pretty(), cache.Pretty(path))
+			}
+		}()
+	}
+	wg.Wait()
+	require.Equal(t, len(prettyPaths), cache.Len())
+}
+
+func BenchmarkPretty(b *testing.B) {
+	path := types.NewMerklePath(prettyPaths[0])
+
+	b.Run("uncached", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_ = path.Pretty()
+		}
+	})
+
+	b.Run("cached", func(b *testing.B) {
+		cache := types.NewPrettyCache()
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_ = cache.Pretty(path)
+		}
+	})
+}
@@ -0,0 +1,50 @@
+package types
+
+import (
+	"sync"
+
+	"github.com/tendermint/tendermint/crypto/merkle"
+)
+
+// proofPool holds the released proofs, so that their allocations can be reused
+// by the proofs decoded next.
+var proofPool = sync.Pool{
+	New: func() interface{} {
+		return &MerkleProof{Proof: &merkle.Proof{}}
+	},
+}
+
+// AcquireProof returns an empty proof from the pool, whose operations slice may
+// have been allocated by a previously released proof. It is meant for relayers
+// decoding large numbers of short-lived proofs (eg: with Unmarshal).
+//
+// CONTRACT: the proof must be released with ReleaseProof once it is no longer
+// used.
+func AcquireProof() *MerkleProof {
+	proof := proofPool.Get().(*MerkleProof)
+	if proof.Proof == nil {
+		proof.Proof = &merkle.Proof{}
+	}
+	return proof
+}
+
+// ReleaseProof resets the proof and returns it to the pool. The operations are
+// cleared so that the pool doesn't retain their keys and data.
+//
+// CONTRACT: neither the proof nor any of its operations may be retained or used
+// after it is released, since they will be overwritten by the next user of the
+// pool.
+func ReleaseProof(proof *MerkleProof) {
+	if proof == nil {
+		return
+	}
+
+	if proof.Proof != nil {
+		for i := range proof.Proof.Ops {
+			proof.Proof.Ops[i] = merkle.ProofOp{}
+		}
+		proof.Proof.Ops = proof.Proof.Ops[:0]
+	}
+
+	proofPool.Put(proof)
+}
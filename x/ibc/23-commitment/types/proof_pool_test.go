@@ -0,0 +1,114 @@
+package types_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/crypto/merkle"
+	dbm "github.com/tendermint/tm-db"
+
+	"github.com/cosmos/cosmos-sdk/store/iavl"
+	"github.com/cosmos/cosmos-sdk/store/rootmulti"
+	storetypes "github.com/cosmos/cosmos-sdk/store/types"
+	"github.com/cosmos/cosmos-sdk/x/ibc/23-commitment/types"
+)
+
+func (suite *MerkleTestSuite) TestAcquireReleaseProof() {
+	suite.iavlStore.Set([]byte("MYKEY"), []byte("MYVALUE"))
+	cid := suite.store.Commit()
+
+	res := suite.store.Query(abci.RequestQuery{
+		Path:   fmt.Sprintf("/%s/key", suite.storeKey.Name()), // required path to get key/value+proof
+		Data:   []byte("MYKEY"),
+		Height: cid.Version,
+		Prove:  true,
+	})
+	suite.Require().NotNil(res.Proof)
+
+	proof := types.MerkleProof{
+		Proof: res.Proof,
+	}
+	bz, err := proof.Marshal()
+	suite.Require().NoError(err)
+
+	root := types.NewMerkleRoot(cid.Hash)
+	path := types.NewMerklePath([]string{suite.storeKey.Name(), "MYKEY"})
+
+	// an acquired proof is empty and decodes and verifies as a new proof
+	pooled := types.AcquireProof()
+	suite.Require().True(pooled.IsEmpty())
+	suite.Require().NoError(pooled.Unmarshal(bz))
+	suite.Require().True(pooled.Equal(proof))
+	suite.Require().NoError(pooled.VerifyMembership(&root, path, []byte("MYVALUE")))
+
+	// releasing the proof clears all of its operations
+	ops := pooled.Proof.Ops
+	types.ReleaseProof(pooled)
+	suite.Require().NotNil(pooled.Proof)
+	suite.Require().Empty(pooled.Proof.Ops)
+	suite.Require().True(pooled.IsEmpty())
+	for _, op := range ops {
+		suite.Require().Equal(merkle.ProofOp{}, op)
+	}
+
+	// proofs without operations or without proof are released and acquired empty
+	types.ReleaseProof(&types.MerkleProof{})
+	types.ReleaseProof(nil)
+	for i := 0; i < 3; i++ {
+		reused := types.AcquireProof()
+		suite.Require().NotNil(reused.Proof)
+		suite.Require().True(reused.IsEmpty())
+		suite.Require().NoError(reused.Unmarshal(bz))
+		suite.Require().NoError(reused.VerifyMembership(&root, path, []byte("MYVALUE")))
+		types.ReleaseProof(reused)
+	}
+}
+
+func BenchmarkAcquireProof(b *testing.B) {
+	store := rootmulti.NewStore(dbm.NewMemDB())
+	storeKey := storetypes.NewKVStoreKey("iavlStoreKey")
+	store.MountStoreWithDB(storeKey, storetypes.StoreTypeIAVL, nil)
+	require.NoError(b, store.LoadVersion(0))
+
+	iavlStore := store.GetCommitStore(storeKey).(*iavl.Store)
+	for i := 0; i < 1000; i++ {
+		iavlStore.Set([]byte(fmt.Sprintf("KEY%d", i)), []byte(fmt.Sprintf("VALUE%d", i)))
+	}
+	cid := store.Commit()
+
+	res := store.Query(abci.RequestQuery{
+		Path:   fmt.Sprintf("/%s/key", storeKey.Name()), // required path to get key/value+proof
+		Data:   []byte("KEY500"),
+		Height: cid.Version,
+		Prove:  true,
+	})
+	proof := types.MerkleProof{
+		Proof: res.Proof,
+	}
+	bz, err := proof.Marshal()
+	require.NoError(b, err)
+
+	b.Run("new", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			var decoded types.MerkleProof
+			if err := decoded.Unmarshal(bz); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("pooled", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			decoded := types.AcquireProof()
+			if err := decoded.Unmarshal(bz); err != nil {
+				b.Fatal(err)
+			}
+			types.ReleaseProof(decoded)
+		}
+	})
+}
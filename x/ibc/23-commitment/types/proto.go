@@ -0,0 +1,46 @@
+package types
+
+import (
+	"errors"
+
+	"github.com/gogo/protobuf/proto"
+
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/cosmos/cosmos-sdk/x/ibc/23-commitment/exported"
+)
+
+// VerifyMembershipProto verifies the membership of a merkle proof against the
+// given root, path and protobuf message. The message is marshaled
+// deterministically before it is verified as the committed value, so that map
+// fields are encoded in the order of their keys.
+//
+// NOTE: messages with a generated Marshal method (ie gogoproto marshalers) are
+// encoded by that method, as gogoproto doesn't support deterministic marshaling
+// for them. Generated marshalers encode fields in order, and are deterministic
+// for messages without map fields.
+func (proof MerkleProof) VerifyMembershipProto(root exported.Root, path exported.Path, msg proto.Message) error {
+	if msg == nil {
+		return errors.New("empty params or proof")
+	}
+
+	bz, err := marshalDeterministic(msg)
+	if err != nil {
+		return sdkerrors.Wrapf(ErrInvalidProof, "failed to marshal %T: %s", msg, err)
+	}
+
+	return proof.VerifyMembership(root, path, bz)
+}
+
+// marshalDeterministic marshals the protobuf message with a deterministic encoding.
+func marshalDeterministic(msg proto.Message) ([]byte, error) {
+	if _, ok := msg.(proto.Marshaler); ok {
+		return proto.Marshal(msg)
+	}
+
+	buf := proto.NewBuffer(nil)
+	buf.SetDeterministic(true)
+	if err := buf.Marshal(msg); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,81 @@
+package types_test
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/gogo/protobuf/proto"
+	abci "github.com/tendermint/tendermint/abci/types"
+
+	"github.com/cosmos/cosmos-sdk/x/ibc/23-commitment/types"
+)
+
+// mapMessage is a protobuf message without a generated marshaler, whose map
+// field is only encoded deterministically when requested
+type mapMessage struct {
+	Fields map[string]string `protobuf:"bytes,1,rep,name=fields,proto3" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (m *mapMessage) Reset()         { *m = mapMessage{} }
+func (m *mapMessage) String() string { return proto.CompactTextString(m) }
+func (*mapMessage) ProtoMessage()    {}
+
+func (suite *MerkleTestSuite) TestVerifyMembershipProto() {
+	msg := &mapMessage{Fields: make(map[string]string)}
+	for i := 0; i < 32; i++ {
+		msg.Fields[fmt.Sprintf("field%d", i)] = fmt.Sprintf("value%d", i)
+	}
+
+	buf := proto.NewBuffer(nil)
+	buf.SetDeterministic(true)
+	suite.Require().NoError(buf.Marshal(msg))
+	committed := buf.Bytes()
+
+	// a message with a generated marshaler
+	prefix := types.NewMerklePrefix([]byte("ibc"))
+	prefixBz, err := prefix.Marshal()
+	suite.Require().NoError(err)
+
+	suite.iavlStore.Set([]byte("MYKEY"), committed)
+	suite.iavlStore.Set([]byte("MYPREFIX"), prefixBz)
+	cid := suite.store.Commit()
+
+	query := func(key string) types.MerkleProof {
+		res := suite.store.Query(abci.RequestQuery{
+			Path:  fmt.Sprintf("/%s/key", suite.storeKey.Name()), // required path to get key/value+proof
+			Data:  []byte(key),
+			Prove: true,
+		})
+		suite.Require().NotNil(res.Proof)
+		return types.MerkleProof{Proof: res.Proof}
+	}
+
+	proof := query("MYKEY")
+	root := types.NewMerkleRoot(cid.Hash)
+	path := types.NewMerklePath([]string{suite.storeKey.Name(), "MYKEY"})
+
+	// a non-deterministic marshal eventually produces an encoding that doesn't
+	// verify, while the deterministic one always verifies
+	mismatch := false
+	for i := 0; i < 20; i++ {
+		bz, err := proto.Marshal(msg)
+		suite.Require().NoError(err)
+		if !bytes.Equal(committed, bz) {
+			mismatch = true
+			suite.Require().Error(proof.VerifyMembership(&root, path, bz))
+		}
+
+		suite.Require().NoError(proof.VerifyMembershipProto(&root, path, msg))
+	}
+	suite.Require().True(mismatch, "expected a non-deterministic encoding")
+
+	other := &mapMessage{Fields: map[string]string{"field0": "value1"}}
+	suite.Require().Error(proof.VerifyMembershipProto(&root, path, other))
+	suite.Require().Error(proof.VerifyMembershipProto(&root, path, nil))
+
+	prefixProof := query("MYPREFIX")
+	prefixPath := types.NewMerklePath([]string{suite.storeKey.Name(), "MYPREFIX"})
+	suite.Require().NoError(prefixProof.VerifyMembershipProto(&root, prefixPath, &prefix))
+	otherPrefix := types.NewMerklePrefix([]byte("other"))
+	suite.Require().Error(prefixProof.VerifyMembershipProto(&root, prefixPath, &otherPrefix))
+}
@@ -0,0 +1,72 @@
+package types
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/cosmos/cosmos-sdk/x/ibc/23-commitment/exported"
+)
+
+// RateLimitedVerifier caps the throughput of proof verifications with a token
+// bucket: it allows bursts of up to burst verifications, refilled at rate
+// verifications per second. Verifications exceeding the limit are rejected with
+// ErrRateLimited without being run. It is safe for concurrent use.
+type RateLimitedVerifier struct {
+	mtx    sync.Mutex
+	rate   float64 // tokens refilled per second
+	burst  float64 // capacity of the bucket
+	tokens float64
+	last   time.Time
+}
+
+// NewRateLimitedVerifier creates a new RateLimitedVerifier with a full bucket.
+func NewRateLimitedVerifier(rate float64, burst int) *RateLimitedVerifier {
+	return &RateLimitedVerifier{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// VerifyMembership verifies the membership of the merkle proof, as
+// MerkleProof.VerifyMembership, if the rate limit allows it.
+func (rlv *RateLimitedVerifier) VerifyMembership(proof MerkleProof, root exported.Root, path exported.Path, value []byte) error {
+	if err := rlv.take(); err != nil {
+		return err
+	}
+
+	return proof.VerifyMembership(root, path, value)
+}
+
+// VerifyNonMembership verifies the absence of the merkle proof's path, as
+// MerkleProof.VerifyNonMembership, if the rate limit allows it.
+func (rlv *RateLimitedVerifier) VerifyNonMembership(proof MerkleProof, root exported.Root, path exported.Path) error {
+	if err := rlv.take(); err != nil {
+		return err
+	}
+
+	return proof.VerifyNonMembership(root, path)
+}
+
+// take refills the bucket for the time elapsed since the last call and takes a
+// token from it, returning ErrRateLimited if the bucket is empty.
+func (rlv *RateLimitedVerifier) take() error {
+	rlv.mtx.Lock()
+	defer rlv.mtx.Unlock()
+
+	now := time.Now()
+	if elapsed := now.Sub(rlv.last).Seconds(); elapsed > 0 {
+		rlv.tokens = math.Min(rlv.burst, rlv.tokens+elapsed*rlv.rate)
+	}
+	rlv.last = now
+
+	if rlv.tokens < 1 {
+		return sdkerrors.Wrapf(ErrRateLimited, "limit of %g verifications per second with a burst of %g", rlv.rate, rlv.burst)
+	}
+
+	rlv.tokens--
+	return nil
+}
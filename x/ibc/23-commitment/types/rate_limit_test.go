@@ -0,0 +1,68 @@
+package types_test
+
+import (
+	"fmt"
+	"time"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+
+	"github.com/cosmos/cosmos-sdk/x/ibc/23-commitment/types"
+)
+
+func (suite *MerkleTestSuite) TestRateLimitedVerifier() {
+	suite.iavlStore.Set([]byte("MYKEY"), []byte("MYVALUE"))
+	cid := suite.store.Commit()
+
+	query := func(key string) types.MerkleProof {
+		res := suite.store.Query(abci.RequestQuery{
+			Path:   fmt.Sprintf("/%s/key", suite.storeKey.Name()), // required path to get key/value+proof
+			Data:   []byte(key),
+			Height: cid.Version,
+			Prove:  true,
+		})
+		suite.Require().NotNil(res.Proof)
+		return types.MerkleProof{Proof: res.Proof}
+	}
+
+	proof := query("MYKEY")
+	absenceProof := query("MYABSENTKEY")
+	root := types.NewMerkleRoot(cid.Hash)
+	path := types.NewMerklePath([]string{suite.storeKey.Name(), "MYKEY"})
+	absentPath := types.NewMerklePath([]string{suite.storeKey.Name(), "MYABSENTKEY"})
+
+	// a burst up to the bucket capacity passes, the sustained overflow is rejected
+	const burst = 5
+	verifier := types.NewRateLimitedVerifier(1.0/3600, burst) // refills once an hour
+	for i := 0; i < burst; i++ {
+		if i%2 == 0 {
+			suite.Require().NoError(verifier.VerifyMembership(proof, &root, path, []byte("MYVALUE")), i)
+		} else {
+			suite.Require().NoError(verifier.VerifyNonMembership(absenceProof, &root, absentPath), i)
+		}
+	}
+	for i := 0; i < 10; i++ {
+		err := verifier.VerifyMembership(proof, &root, path, []byte("MYVALUE"))
+		suite.Require().Error(err, i)
+		suite.Require().True(types.ErrRateLimited.Is(err), i)
+
+		err = verifier.VerifyNonMembership(absenceProof, &root, absentPath)
+		suite.Require().True(types.ErrRateLimited.Is(err), i)
+	}
+
+	// failed verifications also take a token
+	verifier = types.NewRateLimitedVerifier(1.0/3600, 1)
+	err := verifier.VerifyMembership(proof, &root, path, []byte("WRONGVALUE"))
+	suite.Require().Error(err)
+	suite.Require().False(types.ErrRateLimited.Is(err))
+	suite.Require().True(types.ErrRateLimited.Is(verifier.VerifyMembership(proof, &root, path, []byte("MYVALUE"))))
+
+	// the bucket refills over time
+	verifier = types.NewRateLimitedVerifier(1000, 1)
+	suite.Require().NoError(verifier.VerifyMembership(proof, &root, path, []byte("MYVALUE")))
+	time.Sleep(20 * time.Millisecond)
+	suite.Require().NoError(verifier.VerifyMembership(proof, &root, path, []byte("MYVALUE")))
+
+	// a zero burst rejects every verification
+	verifier = types.NewRateLimitedVerifier(1000, 0)
+	suite.Require().True(types.ErrRateLimited.Is(verifier.VerifyMembership(proof, &root, path, []byte("MYVALUE"))))
+}
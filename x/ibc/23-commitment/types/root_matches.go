@@ -0,0 +1,77 @@
+package types
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+
+	"github.com/cosmos/cosmos-sdk/store/rootmulti"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+// RootMatches returns true if the root computed by the proof equals the expected
+// root, encoded either in hex (optionally prefixed by 0x) or in standard or URL
+// base64, as found in headers and block explorers. The encoding is detected from
+// the expected string: hex is tried first as it is the stricter alphabet.
+//
+// NOTE: only the root computed by the outermost proof operation is compared, the
+// proof is not verified against any path or value.
+func (proof MerkleProof) RootMatches(expected string) (bool, error) {
+	if proof.IsEmpty() {
+		return false, sdkerrors.Wrap(ErrInvalidProof, "proof cannot be empty")
+	}
+
+	expectedHash, err := decodeRootHash(expected)
+	if err != nil {
+		return false, err
+	}
+
+	root, err := proof.computeRoot()
+	if err != nil {
+		return false, err
+	}
+
+	return bytes.Equal(root, expectedHash), nil
+}
+
+// computeRoot returns the root computed by the outermost operation of the proof,
+// without verifying the proof.
+func (proof MerkleProof) computeRoot() ([]byte, error) {
+	runtime := rootmulti.DefaultProofRuntime()
+	poz, err := runtime.DecodeProof(proof.Proof)
+	if err != nil {
+		return nil, sdkerrors.Wrap(ErrInvalidProof, err.Error())
+	}
+
+	facts, err := getOperatorFacts(poz[len(poz)-1])
+	if err != nil {
+		return nil, sdkerrors.Wrap(ErrInvalidProof, err.Error())
+	}
+
+	return facts.subroot, nil
+}
+
+// decodeRootHash decodes a root hash from its hex or base64 encoding.
+func decodeRootHash(s string) ([]byte, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, sdkerrors.Wrap(ErrInvalidRoot, "expected root cannot be empty")
+	}
+
+	hexStr := s
+	if strings.HasPrefix(hexStr, "0x") || strings.HasPrefix(hexStr, "0X") {
+		hexStr = hexStr[2:]
+	}
+	if hash, err := hex.DecodeString(hexStr); err == nil && len(hash) != 0 {
+		return hash, nil
+	}
+
+	for _, encoding := range []*base64.Encoding{base64.StdEncoding, base64.URLEncoding} {
+		if hash, err := encoding.DecodeString(s); err == nil && len(hash) != 0 {
+			return hash, nil
+		}
+	}
+
+	return nil, sdkerrors.Wrapf(ErrInvalidRoot, "expected root %s is neither hex nor base64 encoded", s)
+}
@@ -0,0 +1,72 @@
+package types_test
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/crypto/merkle"
+
+	"github.com/cosmos/cosmos-sdk/x/ibc/23-commitment/types"
+)
+
+func (suite *MerkleTestSuite) TestRootMatches() {
+	suite.iavlStore.Set([]byte("MYKEY"), []byte("MYVALUE"))
+	cid := suite.store.Commit()
+
+	res := suite.store.Query(abci.RequestQuery{
+		Path:   fmt.Sprintf("/%s/key", suite.storeKey.Name()), // required path to get key/value+proof
+		Data:   []byte("MYKEY"),
+		Height: cid.Version,
+		Prove:  true,
+	})
+	suite.Require().NotNil(res.Proof)
+
+	proof := types.MerkleProof{
+		Proof: res.Proof,
+	}
+
+	suite.iavlStore.Set([]byte("MYKEY"), []byte("MYNEWVALUE"))
+	otherCid := suite.store.Commit()
+
+	undecodable := types.MerkleProof{Proof: &merkle.Proof{Ops: []merkle.ProofOp{{Type: "unknown", Key: []byte("MYKEY")}}}}
+
+	cases := []struct {
+		name     string
+		proof    types.MerkleProof
+		expected string
+		expMatch bool
+		expPass  bool
+	}{
+		{"upper case hex", proof, strings.ToUpper(hex.EncodeToString(cid.Hash)), true, true},
+		{"lower case hex", proof, hex.EncodeToString(cid.Hash), true, true},
+		{"0x prefixed hex", proof, "0x" + hex.EncodeToString(cid.Hash), true, true},
+		{"standard base64", proof, base64.StdEncoding.EncodeToString(cid.Hash), true, true},
+		{"url base64", proof, base64.URLEncoding.EncodeToString(cid.Hash), true, true},
+		{"surrounding whitespace", proof, " " + hex.EncodeToString(cid.Hash) + "\n", true, true},
+		{"other root in hex", proof, hex.EncodeToString(otherCid.Hash), false, true},
+		{"other root in base64", proof, base64.StdEncoding.EncodeToString(otherCid.Hash), false, true},
+		{"empty expected root", proof, "", false, false},
+		{"undecodable expected root", proof, "not a root!", false, false},
+		{"empty proof", types.MerkleProof{}, hex.EncodeToString(cid.Hash), false, false},
+		{"undecodable proof", undecodable, hex.EncodeToString(cid.Hash), false, false},
+	}
+
+	for i, tc := range cases {
+		tc := tc
+		suite.Run(tc.name, func() {
+			match, err := tc.proof.RootMatches(tc.expected)
+
+			if tc.expPass {
+				// nolint: scopelint
+				suite.Require().NoError(err, "test case %d should have passed", i)
+			} else {
+				// nolint: scopelint
+				suite.Require().Error(err, "test case %d should have failed", i)
+			}
+			suite.Require().Equal(tc.expMatch, match, "test case %d", i)
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package types
+
+import (
+	"time"
+
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/cosmos/cosmos-sdk/x/ibc/23-commitment/exported"
+)
+
+// RootProvider defines the interface of the types, such as a light client,
+// that are able to lazily provide the commitment root of a given height.
+type RootProvider interface {
+	RootAtHeight(height uint64) (exported.Root, error)
+}
+
+// TimestampProvider defines the interface a RootProvider may implement to
+// provide the timestamp, in nanoseconds, of the root of a given height.
+type TimestampProvider interface {
+	TimestampAtHeight(height uint64) (uint64, error)
+}
+
+// ClientStatus defines the status of the client providing commitment roots.
+type ClientStatus string
+
+// Client statuses
+const (
+	ClientStatusActive  ClientStatus = "active"
+	ClientStatusFrozen  ClientStatus = "frozen"
+	ClientStatusExpired ClientStatus = "expired"
+)
+
+// ClientStatusProvider defines the interface a RootProvider may implement to
+// report the status of its client. Roots of a frozen or expired client remain
+// cryptographically valid but must no longer be trusted.
+type ClientStatusProvider interface {
+	ClientStatus() ClientStatus
+}
+
+// VerifyMembershipFromProvider verifies the membership of a merkle proof against
+// the root provided for the given height. If the provider implements
+// ClientStatusProvider, the proof is rejected before verification unless the
+// client is active.
+func (proof MerkleProof) VerifyMembershipFromProvider(rp RootProvider, height uint64, path exported.Path, value []byte) error {
+	if rp == nil {
+		return sdkerrors.Wrap(ErrInvalidProof, "root provider cannot be nil")
+	}
+
+	if csp, ok := rp.(ClientStatusProvider); ok {
+		switch status := csp.ClientStatus(); status {
+		case ClientStatusActive:
+		case ClientStatusFrozen:
+			return sdkerrors.Wrapf(ErrClientFrozen, "cannot verify proof at height %d", height)
+		case ClientStatusExpired:
+			return sdkerrors.Wrapf(ErrClientExpired, "cannot verify proof at height %d", height)
+		default:
+			return sdkerrors.Wrapf(ErrInvalidProof, "unknown client status %s", status)
+		}
+	}
+
+	root, err := rp.RootAtHeight(height)
+	if err != nil {
+		return sdkerrors.Wrapf(err, "cannot retrieve root at height %d", height)
+	}
+
+	return proof.VerifyMembership(root, path, value)
+}
+
+// VerifyMembershipWithMaxAge verifies the membership of a merkle proof against
+// the root provided for the given height, as VerifyMembershipFromProvider, if
+// the root is no older than the maximum age at the current time, given in
+// nanoseconds. The provider must implement TimestampProvider.
+func (proof MerkleProof) VerifyMembershipWithMaxAge(
+	rp RootProvider, height, currentTime uint64, maxAge time.Duration, path exported.Path, value []byte,
+) error {
+	tp, ok := rp.(TimestampProvider)
+	if !ok {
+		return sdkerrors.Wrap(ErrInvalidProof, "root provider cannot provide root timestamps")
+	}
+
+	timestamp, err := tp.TimestampAtHeight(height)
+	if err != nil {
+		return sdkerrors.Wrapf(err, "cannot retrieve root timestamp at height %d", height)
+	}
+
+	if currentTime > timestamp && currentTime-timestamp > uint64(maxAge) {
+		return sdkerrors.Wrapf(
+			ErrInvalidRoot, "root at height %d is older than the maximum age %s (%s)",
+			height, maxAge, time.Duration(currentTime-timestamp),
+		)
+	}
+
+	return proof.VerifyMembershipFromProvider(rp, height, path, value)
+}
@@ -0,0 +1,188 @@
+package types_test
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+	"github.com/cosmos/cosmos-sdk/x/ibc/23-commitment/exported"
+	"github.com/cosmos/cosmos-sdk/x/ibc/23-commitment/types"
+)
+
+var _ types.RootProvider = stubRootProvider{}
+
+// stubRootProvider provides the roots for a fixed set of heights
+type stubRootProvider map[uint64][]byte
+
+func (rp stubRootProvider) RootAtHeight(height uint64) (exported.Root, error) {
+	hash, ok := rp[height]
+	if !ok {
+		return nil, errors.New("root not found")
+	}
+
+	root := types.NewMerkleRoot(hash)
+	return &root, nil
+}
+
+var _ types.ClientStatusProvider = statusRootProvider{}
+
+// statusRootProvider provides the roots of a stubRootProvider for a client with
+// the given status
+type statusRootProvider struct {
+	stubRootProvider
+	status types.ClientStatus
+}
+
+func (rp statusRootProvider) ClientStatus() types.ClientStatus {
+	return rp.status
+}
+
+var _ types.TimestampProvider = timestampRootProvider{}
+
+// timestampRootProvider provides the roots of a stubRootProvider along with
+// their timestamps
+type timestampRootProvider struct {
+	stubRootProvider
+	timestamps map[uint64]uint64
+}
+
+func (rp timestampRootProvider) TimestampAtHeight(height uint64) (uint64, error) {
+	timestamp, ok := rp.timestamps[height]
+	if !ok {
+		return 0, errors.New("timestamp not found")
+	}
+	return timestamp, nil
+}
+
+func (suite *MerkleTestSuite) TestVerifyMembershipFromProvider() {
+	suite.iavlStore.Set([]byte("MYKEY"), []byte("MYVALUE"))
+	cid := suite.store.Commit()
+
+	res := suite.store.Query(abci.RequestQuery{
+		Path:  fmt.Sprintf("/%s/key", suite.storeKey.Name()), // required path to get key/value+proof
+		Data:  []byte("MYKEY"),
+		Prove: true,
+	})
+	suite.Require().NotNil(res.Proof)
+
+	proof := types.MerkleProof{
+		Proof: res.Proof,
+	}
+	path := types.NewMerklePath([]string{suite.storeKey.Name(), "MYKEY"})
+	height := uint64(cid.Version)
+
+	provider := stubRootProvider{
+		height:     cid.Hash,
+		height + 1: []byte("WRONGROOT"),
+	}
+
+	cases := []struct {
+		name     string
+		provider types.RootProvider
+		height   uint64
+		value    []byte
+		expPass  bool
+	}{
+		{"valid proof", provider, height, []byte("MYVALUE"), true},
+		{"wrong value", provider, height, []byte("WRONGVALUE"), false},
+		{"wrong root", provider, height + 1, []byte("MYVALUE"), false},
+		{"root not found", provider, height + 2, []byte("MYVALUE"), false},
+		{"nil provider", nil, height, []byte("MYVALUE"), false},
+		{"active client", statusRootProvider{provider, types.ClientStatusActive}, height, []byte("MYVALUE"), true},
+		{"active client with wrong value", statusRootProvider{provider, types.ClientStatusActive}, height, []byte("WRONGVALUE"), false},
+	}
+
+	for i, tc := range cases {
+		tc := tc
+		suite.Run(tc.name, func() {
+			err := proof.VerifyMembershipFromProvider(tc.provider, tc.height, path, tc.value)
+
+			if tc.expPass {
+				// nolint: scopelint
+				suite.Require().NoError(err, "test case %d should have passed", i)
+			} else {
+				// nolint: scopelint
+				suite.Require().Error(err, "test case %d should have failed", i)
+			}
+		})
+	}
+
+	// proofs are rejected at the policy level, even if cryptographically valid
+	statusCases := []struct {
+		status types.ClientStatus
+		expErr *sdkerrors.Error
+	}{
+		{types.ClientStatusFrozen, types.ErrClientFrozen},
+		{types.ClientStatusExpired, types.ErrClientExpired},
+		{types.ClientStatus("unknown"), types.ErrInvalidProof},
+	}
+
+	for _, tc := range statusCases {
+		err := proof.VerifyMembershipFromProvider(statusRootProvider{provider, tc.status}, height, path, []byte("MYVALUE"))
+		suite.Require().Error(err, string(tc.status))
+		suite.Require().True(tc.expErr.Is(err), err.Error())
+	}
+}
+
+func (suite *MerkleTestSuite) TestVerifyMembershipWithMaxAge() {
+	suite.iavlStore.Set([]byte("MYKEY"), []byte("MYVALUE"))
+	cid := suite.store.Commit()
+
+	res := suite.store.Query(abci.RequestQuery{
+		Path:   fmt.Sprintf("/%s/key", suite.storeKey.Name()), // required path to get key/value+proof
+		Data:   []byte("MYKEY"),
+		Height: cid.Version,
+		Prove:  true,
+	})
+	suite.Require().NotNil(res.Proof)
+
+	proof := types.MerkleProof{
+		Proof: res.Proof,
+	}
+	path := types.NewMerklePath([]string{suite.storeKey.Name(), "MYKEY"})
+	height := uint64(cid.Version)
+
+	rootTime := uint64(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC).UnixNano())
+	maxAge := time.Hour
+
+	provider := timestampRootProvider{
+		stubRootProvider: stubRootProvider{height: cid.Hash},
+		timestamps:       map[uint64]uint64{height: rootTime},
+	}
+
+	cases := []struct {
+		name        string
+		provider    types.RootProvider
+		height      uint64
+		currentTime uint64
+		value       []byte
+		expPass     bool
+	}{
+		{"fresh root", provider, height, rootTime + uint64(time.Minute), []byte("MYVALUE"), true},
+		{"root exactly at max age", provider, height, rootTime + uint64(maxAge), []byte("MYVALUE"), true},
+		{"root from the future", provider, height, rootTime - uint64(time.Minute), []byte("MYVALUE"), true},
+		{"stale root", provider, height, rootTime + uint64(maxAge) + 1, []byte("MYVALUE"), false},
+		{"fresh root with wrong value", provider, height, rootTime, []byte("WRONGVALUE"), false},
+		{"timestamp not found", provider, height + 1, rootTime, []byte("MYVALUE"), false},
+		{"provider without timestamps", provider.stubRootProvider, height, rootTime, []byte("MYVALUE"), false},
+		{"nil provider", nil, height, rootTime, []byte("MYVALUE"), false},
+	}
+
+	for i, tc := range cases {
+		tc := tc
+		suite.Run(tc.name, func() {
+			err := proof.VerifyMembershipWithMaxAge(tc.provider, tc.height, tc.currentTime, maxAge, path, tc.value)
+
+			if tc.expPass {
+				// nolint: scopelint
+				suite.Require().NoError(err, "test case %d should have passed", i)
+			} else {
+				// nolint: scopelint
+				suite.Require().Error(err, "test case %d should have failed", i)
+			}
+		})
+	}
+}
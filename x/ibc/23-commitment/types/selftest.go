@@ -0,0 +1,69 @@
+package types
+
+import (
+	"fmt"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+	dbm "github.com/tendermint/tm-db"
+
+	"github.com/cosmos/cosmos-sdk/store/rootmulti"
+	storetypes "github.com/cosmos/cosmos-sdk/store/types"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+const selfTestStoreName = "selftest"
+
+// SelfTest commits a key to an in-memory IAVL store mounted on a multistore and
+// checks that the proofs queried for it verify membership and non-membership
+// with the default proof runtime. It is intended to be run on startup to catch
+// store and proof runtime mismatches early.
+func SelfTest() error {
+	store := rootmulti.NewStore(dbm.NewMemDB())
+	storeKey := storetypes.NewKVStoreKey(selfTestStoreName)
+	store.MountStoreWithDB(storeKey, storetypes.StoreTypeIAVL, nil)
+	if err := store.LoadLatestVersion(); err != nil {
+		return sdkerrors.Wrap(err, "self test: failed to load store")
+	}
+
+	key, value := []byte("key"), []byte("value")
+	store.GetCommitKVStore(storeKey).Set(key, value)
+	cid := store.Commit()
+	root := NewMerkleRoot(cid.Hash)
+
+	query := func(key []byte) (MerkleProof, error) {
+		res := store.Query(abci.RequestQuery{
+			Path:   fmt.Sprintf("/%s/key", selfTestStoreName), // required path to get key/value+proof
+			Data:   key,
+			Height: cid.Version,
+			Prove:  true,
+		})
+		if res.Proof == nil {
+			return MerkleProof{}, sdkerrors.Wrapf(ErrInvalidProof, "self test: no proof for key %s: %s", key, res.Log)
+		}
+		return MerkleProof{Proof: res.Proof}, nil
+	}
+
+	proof, err := query(key)
+	if err != nil {
+		return err
+	}
+	path := NewMerklePath([]string{selfTestStoreName, string(key)})
+	if err := proof.VerifyMembership(&root, path, value); err != nil {
+		return sdkerrors.Wrap(err, "self test: membership proof failed")
+	}
+	if err := proof.VerifyMembership(&root, path, []byte("invalid")); err == nil {
+		return sdkerrors.Wrap(ErrInvalidProof, "self test: membership proof verified an invalid value")
+	}
+
+	absentKey := []byte("keyabsent")
+	proof, err = query(absentKey)
+	if err != nil {
+		return err
+	}
+	path = NewMerklePath([]string{selfTestStoreName, string(absentKey)})
+	if err := proof.VerifyNonMembership(&root, path); err != nil {
+		return sdkerrors.Wrap(err, "self test: non-membership proof failed")
+	}
+
+	return nil
+}
@@ -0,0 +1,13 @@
+package types_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cosmos/cosmos-sdk/x/ibc/23-commitment/types"
+)
+
+func TestSelfTest(t *testing.T) {
+	require.NoError(t, types.SelfTest())
+}
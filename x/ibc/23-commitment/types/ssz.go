@@ -0,0 +1,330 @@
+package types
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/tendermint/iavl"
+	"github.com/tendermint/tendermint/crypto/merkle"
+	"github.com/tendermint/tendermint/crypto/tmhash"
+	"github.com/tendermint/tendermint/libs/kv"
+
+	"github.com/cosmos/cosmos-sdk/store/rootmulti"
+	sdkerrors "github.com/cosmos/cosmos-sdk/types/errors"
+)
+
+const (
+	sszOffsetSize    = 4
+	sszHashSize      = tmhash.Size
+	sszInnerNodeSize = 1 + 8 + 8 + 1 + sszHashSize
+	// key offset, value hash, leaf version, inner nodes offset, store name offset,
+	// store index, store total and store aunts offset
+	sszFixedSize = sszOffsetSize + sszHashSize + 8 + sszOffsetSize + sszOffsetSize + 8 + 8 + sszOffsetSize
+)
+
+// SSZInnerNode defines an inner node of the IAVL path from a leaf to the store
+// root, reduced to the fields hashed with the child hash and the sibling hash.
+type SSZInnerNode struct {
+	Height        int8
+	Size          int64
+	Version       int64
+	SiblingIsLeft bool
+	Sibling       []byte
+}
+
+// SSZExistenceProof defines the existence proof of a key in an IAVL store of the
+// multistore, reduced to the leaf and the sibling hashes required to compute the
+// multistore root. It is encoded as the following SSZ container, where the
+// signed integers are encoded as their unsigned value:
+//
+//	class ExistenceProof(Container):
+//	    key: List[byte, N]
+//	    value_hash: Bytes32             # sha256 of the value
+//	    leaf_version: uint64
+//	    inner_nodes: List[InnerNode, N] # from the leaf up to the store root
+//	    store_name: List[byte, N]
+//	    store_index: uint64             # index of the store in the multistore
+//	    store_total: uint64             # number of stores in the multistore
+//	    store_aunts: List[Bytes32, N]   # from the store leaf up to the multistore root
+//
+//	class InnerNode(Container):
+//	    height: uint8
+//	    size: uint64
+//	    version: uint64
+//	    sibling_is_left: boolean
+//	    sibling: Bytes32
+//
+// The store root is the IAVL hash of the leaf up the inner nodes, and the
+// multistore root is the simple merkle root of the store leaf, which commits to
+// the store name and to the store root, up the store aunts. See ComputeRoot.
+type SSZExistenceProof struct {
+	Key         []byte
+	ValueHash   []byte
+	LeafVersion int64
+	InnerNodes  []SSZInnerNode
+	StoreName   []byte
+	StoreIndex  uint64
+	StoreTotal  uint64
+	StoreAunts  [][]byte
+}
+
+// NewSSZExistenceProof creates the SSZ existence proof of a membership proof made
+// of an IAVL value operation of a single key followed by a multistore operation.
+func NewSSZExistenceProof(proof MerkleProof) (SSZExistenceProof, error) {
+	if proof.IsEmpty() || len(proof.Proof.Ops) != 2 {
+		return SSZExistenceProof{}, sdkerrors.Wrap(ErrInvalidProof, "expected an IAVL and a multistore proof operation")
+	}
+
+	runtime := rootmulti.DefaultProofRuntime()
+	poz, err := runtime.DecodeProof(proof.Proof)
+	if err != nil {
+		return SSZExistenceProof{}, sdkerrors.Wrap(ErrInvalidProof, err.Error())
+	}
+
+	valueOp, ok := poz[0].(iavl.ValueOp)
+	if !ok || valueOp.Proof == nil || len(valueOp.Proof.Leaves) != 1 || len(valueOp.Proof.InnerNodes) != 0 {
+		return SSZExistenceProof{}, sdkerrors.Wrapf(ErrInvalidProof, "expected an %s proof operation of a single key", iavl.ProofOpIAVLValue)
+	}
+
+	storeOp, ok := poz[1].(rootmulti.MultiStoreProofOp)
+	if !ok || storeOp.Proof == nil {
+		return SSZExistenceProof{}, sdkerrors.Wrapf(ErrInvalidProof, "expected a %s proof operation", rootmulti.ProofOpMultiStore)
+	}
+
+	leaf := valueOp.Proof.Leaves[0]
+	sszProof := SSZExistenceProof{
+		Key:         leaf.Key,
+		ValueHash:   leaf.ValueHash,
+		LeafVersion: leaf.Version,
+		InnerNodes:  make([]SSZInnerNode, len(valueOp.Proof.LeftPath)),
+		StoreName:   storeOp.GetKey(),
+	}
+
+	// the left path is ordered from the store root down to the leaf
+	for i, pin := range valueOp.Proof.LeftPath {
+		node := SSZInnerNode{
+			Height:        pin.Height,
+			Size:          pin.Size,
+			Version:       pin.Version,
+			SiblingIsLeft: len(pin.Left) != 0,
+			Sibling:       pin.Right,
+		}
+		if node.SiblingIsLeft {
+			node.Sibling = pin.Left
+		}
+		sszProof.InnerNodes[len(sszProof.InnerNodes)-1-i] = node
+	}
+
+	storeHashes := make(map[string][]byte, len(storeOp.Proof.StoreInfos))
+	for _, si := range storeOp.Proof.StoreInfos {
+		storeHashes[si.Name] = si.Hash()
+	}
+
+	_, storeProofs, _ := merkle.SimpleProofsFromMap(storeHashes)
+	storeProof, ok := storeProofs[string(sszProof.StoreName)]
+	if !ok {
+		return SSZExistenceProof{}, sdkerrors.Wrapf(ErrInvalidProof, "store %s not found in the multistore proof", sszProof.StoreName)
+	}
+
+	sszProof.StoreIndex = uint64(storeProof.Index)
+	sszProof.StoreTotal = uint64(storeProof.Total)
+	sszProof.StoreAunts = storeProof.Aunts
+
+	if err := sszProof.ValidateBasic(); err != nil {
+		return SSZExistenceProof{}, err
+	}
+
+	return sszProof, nil
+}
+
+// ValidateBasic checks that the hashes have the SSZ hash size and that the
+// signed integers are not negative.
+func (p SSZExistenceProof) ValidateBasic() error {
+	if len(p.ValueHash) != sszHashSize {
+		return sdkerrors.Wrapf(ErrInvalidProof, "invalid value hash length %d", len(p.ValueHash))
+	}
+	if p.LeafVersion < 0 {
+		return sdkerrors.Wrapf(ErrInvalidProof, "negative leaf version %d", p.LeafVersion)
+	}
+
+	for i, node := range p.InnerNodes {
+		if node.Height < 0 || node.Size < 0 || node.Version < 0 {
+			return sdkerrors.Wrapf(ErrInvalidProof, "inner node #%d has negative fields", i)
+		}
+		if len(node.Sibling) != sszHashSize {
+			return sdkerrors.Wrapf(ErrInvalidProof, "inner node #%d has invalid sibling hash length %d", i, len(node.Sibling))
+		}
+	}
+
+	if p.StoreIndex >= p.StoreTotal {
+		return sdkerrors.Wrapf(ErrInvalidProof, "store index %d out of %d stores", p.StoreIndex, p.StoreTotal)
+	}
+
+	for i, aunt := range p.StoreAunts {
+		if len(aunt) != sszHashSize {
+			return sdkerrors.Wrapf(ErrInvalidProof, "store aunt #%d has invalid hash length %d", i, len(aunt))
+		}
+	}
+
+	return nil
+}
+
+// ComputeRoot computes the multistore root committed by the existence proof, as
+// an on-chain verifier of the SSZ encoding must.
+func (p SSZExistenceProof) ComputeRoot() []byte {
+	hash := iavl.ProofLeafNode{Key: p.Key, ValueHash: p.ValueHash, Version: p.LeafVersion}.Hash()
+
+	for _, node := range p.InnerNodes {
+		pin := iavl.ProofInnerNode{Height: node.Height, Size: node.Size, Version: node.Version}
+		if node.SiblingIsLeft {
+			pin.Left = node.Sibling
+		} else {
+			pin.Right = node.Sibling
+		}
+		hash = pin.Hash(hash)
+	}
+
+	// the store leaf commits to the store name and to the hash of the store info
+	// hash, which is the hash of the store root
+	storeLeaf := merkle.KVPair(kv.Pair{Key: p.StoreName, Value: tmhash.Sum(tmhash.Sum(hash))}).Bytes()
+	storeProof := merkle.SimpleProof{
+		Total:    int(p.StoreTotal),
+		Index:    int(p.StoreIndex),
+		LeafHash: tmhash.Sum(append([]byte{0}, storeLeaf...)), // RFC 6962 leaf prefix
+		Aunts:    p.StoreAunts,
+	}
+
+	return storeProof.ComputeRootHash()
+}
+
+// MarshalSSZ encodes the existence proof as its SSZ container.
+func (p SSZExistenceProof) MarshalSSZ() ([]byte, error) {
+	if err := p.ValidateBasic(); err != nil {
+		return nil, err
+	}
+
+	size := sszFixedSize + len(p.Key) + len(p.InnerNodes)*sszInnerNodeSize + len(p.StoreName) + len(p.StoreAunts)*sszHashSize
+	bz := make([]byte, 0, size)
+
+	offset := sszFixedSize
+	bz = sszAppendOffset(bz, offset)
+	offset += len(p.Key)
+	bz = append(bz, p.ValueHash...)
+	bz = sszAppendUint64(bz, uint64(p.LeafVersion))
+	bz = sszAppendOffset(bz, offset)
+	offset += len(p.InnerNodes) * sszInnerNodeSize
+	bz = sszAppendOffset(bz, offset)
+	offset += len(p.StoreName)
+	bz = sszAppendUint64(bz, p.StoreIndex)
+	bz = sszAppendUint64(bz, p.StoreTotal)
+	bz = sszAppendOffset(bz, offset)
+
+	bz = append(bz, p.Key...)
+	for _, node := range p.InnerNodes {
+		bz = append(bz, uint8(node.Height))
+		bz = sszAppendUint64(bz, uint64(node.Size))
+		bz = sszAppendUint64(bz, uint64(node.Version))
+		if node.SiblingIsLeft {
+			bz = append(bz, 1)
+		} else {
+			bz = append(bz, 0)
+		}
+		bz = append(bz, node.Sibling...)
+	}
+	bz = append(bz, p.StoreName...)
+	for _, aunt := range p.StoreAunts {
+		bz = append(bz, aunt...)
+	}
+
+	return bz, nil
+}
+
+// UnmarshalSSZ decodes the existence proof from its SSZ container.
+func (p *SSZExistenceProof) UnmarshalSSZ(bz []byte) error {
+	if len(bz) < sszFixedSize {
+		return sdkerrors.Wrapf(ErrInvalidProof, "SSZ proof too short: %d bytes", len(bz))
+	}
+
+	keyOffset := binary.LittleEndian.Uint32(bz[0:])
+	nodesOffset := binary.LittleEndian.Uint32(bz[44:])
+	nameOffset := binary.LittleEndian.Uint32(bz[48:])
+	auntsOffset := binary.LittleEndian.Uint32(bz[68:])
+
+	if keyOffset != sszFixedSize || nodesOffset < keyOffset || nameOffset < nodesOffset ||
+		auntsOffset < nameOffset || int(auntsOffset) > len(bz) {
+		return sdkerrors.Wrap(ErrInvalidProof, "invalid SSZ offsets")
+	}
+
+	nodesBz, auntsBz := bz[nodesOffset:nameOffset], bz[auntsOffset:]
+	if len(nodesBz)%sszInnerNodeSize != 0 || len(auntsBz)%sszHashSize != 0 {
+		return sdkerrors.Wrap(ErrInvalidProof, "invalid SSZ list lengths")
+	}
+
+	decoded := SSZExistenceProof{
+		Key:         sszCopy(bz[keyOffset:nodesOffset]),
+		ValueHash:   sszCopy(bz[4:36]),
+		LeafVersion: int64(binary.LittleEndian.Uint64(bz[36:])),
+		StoreName:   sszCopy(bz[nameOffset:auntsOffset]),
+		StoreIndex:  binary.LittleEndian.Uint64(bz[52:]),
+		StoreTotal:  binary.LittleEndian.Uint64(bz[60:]),
+	}
+
+	for i := 0; i < len(nodesBz); i += sszInnerNodeSize {
+		nodeBz := nodesBz[i : i+sszInnerNodeSize]
+		if nodeBz[17] > 1 {
+			return sdkerrors.Wrapf(ErrInvalidProof, "invalid SSZ boolean %d", nodeBz[17])
+		}
+
+		decoded.InnerNodes = append(decoded.InnerNodes, SSZInnerNode{
+			Height:        int8(nodeBz[0]),
+			Size:          int64(binary.LittleEndian.Uint64(nodeBz[1:])),
+			Version:       int64(binary.LittleEndian.Uint64(nodeBz[9:])),
+			SiblingIsLeft: nodeBz[17] == 1,
+			Sibling:       sszCopy(nodeBz[18:]),
+		})
+	}
+
+	for i := 0; i < len(auntsBz); i += sszHashSize {
+		decoded.StoreAunts = append(decoded.StoreAunts, sszCopy(auntsBz[i:i+sszHashSize]))
+	}
+
+	if err := decoded.ValidateBasic(); err != nil {
+		return err
+	}
+
+	*p = decoded
+	return nil
+}
+
+// MarshalSSZ encodes the merkle proof as the SSZ container of its existence
+// proof, for on-chain verification by Ethereum light clients. See
+// SSZExistenceProof for the layout.
+func (proof MerkleProof) MarshalSSZ() ([]byte, error) {
+	sszProof, err := NewSSZExistenceProof(proof)
+	if err != nil {
+		return nil, fmt.Errorf("cannot encode proof as SSZ: %w", err)
+	}
+
+	return sszProof.MarshalSSZ()
+}
+
+func sszAppendOffset(bz []byte, offset int) []byte {
+	var buf [sszOffsetSize]byte
+	binary.LittleEndian.PutUint32(buf[:], uint32(offset))
+	return append(bz, buf[:]...)
+}
+
+func sszAppendUint64(bz []byte, n uint64) []byte {
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], n)
+	return append(bz, buf[:]...)
+}
+
+// sszCopy copies the decoded bytes so that they don't alias the SSZ encoding.
+// Empty byte lists are decoded as nil.
+func sszCopy(bz []byte) []byte {
+	if len(bz) == 0 {
+		return nil
+	}
+	return append([]byte{}, bz...)
+}
@@ -0,0 +1,155 @@
+package types_test
+
+import (
+	"fmt"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/crypto/merkle"
+	"github.com/tendermint/tendermint/crypto/tmhash"
+	dbm "github.com/tendermint/tm-db"
+
+	"github.com/cosmos/cosmos-sdk/store/rootmulti"
+	storetypes "github.com/cosmos/cosmos-sdk/store/types"
+	"github.com/cosmos/cosmos-sdk/x/ibc/23-commitment/types"
+)
+
+func (suite *MerkleTestSuite) TestMarshalSSZ() {
+	// mount several stores so that the store leaf has sibling hashes
+	store := rootmulti.NewStore(dbm.NewMemDB())
+	storeKeys := []*storetypes.KVStoreKey{
+		storetypes.NewKVStoreKey("acc"), storetypes.NewKVStoreKey("bank"), storetypes.NewKVStoreKey("ibc"),
+	}
+	for _, key := range storeKeys {
+		store.MountStoreWithDB(key, storetypes.StoreTypeIAVL, nil)
+	}
+	suite.Require().NoError(store.LoadVersion(0))
+
+	ibcStore := store.GetCommitKVStore(storeKeys[2])
+	for i := 0; i < 10; i++ {
+		ibcStore.Set([]byte(fmt.Sprintf("KEY%d", i)), []byte(fmt.Sprintf("VALUE%d", i)))
+	}
+	store.GetCommitKVStore(storeKeys[0]).Set([]byte("ACC"), []byte("ACCVALUE"))
+	cid := store.Commit()
+
+	query := func(key string) types.MerkleProof {
+		res := store.Query(abci.RequestQuery{
+			Path:   fmt.Sprintf("/%s/key", storeKeys[2].Name()), // required path to get key/value+proof
+			Data:   []byte(key),
+			Height: cid.Version,
+			Prove:  true,
+		})
+		suite.Require().NotNil(res.Proof)
+		return types.MerkleProof{Proof: res.Proof}
+	}
+
+	proof := query("KEY4")
+	bz, err := proof.MarshalSSZ()
+	suite.Require().NoError(err)
+
+	expProof, err := types.NewSSZExistenceProof(proof)
+	suite.Require().NoError(err)
+	suite.Require().Equal([]byte("KEY4"), expProof.Key)
+	suite.Require().Equal(tmhash.Sum([]byte("VALUE4")), expProof.ValueHash)
+	suite.Require().Equal([]byte("ibc"), expProof.StoreName)
+	suite.Require().Equal(uint64(2), expProof.StoreIndex)
+	suite.Require().Equal(uint64(3), expProof.StoreTotal)
+	suite.Require().NotEmpty(expProof.InnerNodes)
+	suite.Require().NotEmpty(expProof.StoreAunts)
+
+	// the decoded proof is the proof that was encoded and commits to the multistore root
+	var sszProof types.SSZExistenceProof
+	suite.Require().NoError(sszProof.UnmarshalSSZ(bz))
+	suite.Require().Equal(expProof, sszProof)
+	suite.Require().Equal(cid.Hash, sszProof.ComputeRoot())
+
+	reencoded, err := sszProof.MarshalSSZ()
+	suite.Require().NoError(err)
+	suite.Require().Equal(bz, reencoded)
+
+	// the proof of another key commits to the same root
+	otherBz, err := query("KEY9").MarshalSSZ()
+	suite.Require().NoError(err)
+	suite.Require().NotEqual(bz, otherBz)
+
+	var otherProof types.SSZExistenceProof
+	suite.Require().NoError(otherProof.UnmarshalSSZ(otherBz))
+	suite.Require().Equal(cid.Hash, otherProof.ComputeRoot())
+
+	// a tampered value hash no longer commits to the root
+	sszProof.ValueHash = tmhash.Sum([]byte("WRONGVALUE"))
+	suite.Require().NotEqual(cid.Hash, sszProof.ComputeRoot())
+
+	cases := []struct {
+		name  string
+		proof types.MerkleProof
+	}{
+		{"empty proof", types.MerkleProof{}},
+		{"absence proof", query("MISSINGKEY")},
+		{"store operation only", types.MerkleProof{Proof: &merkle.Proof{Ops: proof.Proof.Ops[:1]}}},
+		{"multistore operation first", types.MerkleProof{Proof: &merkle.Proof{Ops: []merkle.ProofOp{proof.Proof.Ops[1], proof.Proof.Ops[0]}}}},
+	}
+
+	for i, tc := range cases {
+		_, err := tc.proof.MarshalSSZ()
+		suite.Require().Error(err, "test case %d: %s", i, tc.name)
+	}
+}
+
+func (suite *MerkleTestSuite) TestUnmarshalSSZ() {
+	sszProof := types.SSZExistenceProof{
+		Key:         []byte("MYKEY"),
+		ValueHash:   tmhash.Sum([]byte("MYVALUE")),
+		LeafVersion: 1,
+		InnerNodes: []types.SSZInnerNode{
+			{Height: 1, Size: 2, Version: 1, SiblingIsLeft: true, Sibling: tmhash.Sum([]byte("LEFT"))},
+			{Height: 2, Size: 3, Version: 1, SiblingIsLeft: false, Sibling: tmhash.Sum([]byte("RIGHT"))},
+		},
+		StoreName:  []byte("ibc"),
+		StoreIndex: 0,
+		StoreTotal: 2,
+		StoreAunts: [][]byte{tmhash.Sum([]byte("AUNT"))},
+	}
+
+	bz, err := sszProof.MarshalSSZ()
+	suite.Require().NoError(err)
+	// fixed part, key, inner nodes, store name and store aunts
+	suite.Require().Len(bz, 72+5+2*50+3+32)
+
+	var decoded types.SSZExistenceProof
+	suite.Require().NoError(decoded.UnmarshalSSZ(bz))
+	suite.Require().Equal(sszProof, decoded)
+
+	withBoolean := func(b byte) []byte {
+		bz := append([]byte{}, bz...)
+		bz[72+5+17] = b
+		return bz
+	}
+
+	withOffset := func(i int, offset byte) []byte {
+		bz := append([]byte{}, bz...)
+		bz[i] = offset
+		return bz
+	}
+
+	cases := []struct {
+		name string
+		bz   []byte
+	}{
+		{"empty", nil},
+		{"truncated fixed part", bz[:71]},
+		{"truncated store aunts", bz[:len(bz)-1]},
+		{"invalid key offset", withOffset(0, 73)},
+		{"decreasing offsets", withOffset(48, 0)},
+		{"invalid boolean", withBoolean(2)},
+	}
+
+	for i, tc := range cases {
+		err := decoded.UnmarshalSSZ(tc.bz)
+		suite.Require().Error(err, "test case %d: %s", i, tc.name)
+		suite.Require().Equal(sszProof, decoded, "test case %d: %s", i, tc.name)
+	}
+
+	sszProof.StoreIndex = 2
+	_, err = sszProof.MarshalSSZ()
+	suite.Require().Error(err)
+}
@@ -0,0 +1,73 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/tendermint/tendermint/libs/log"
+
+	"github.com/cosmos/cosmos-sdk/x/ibc/23-commitment/exported"
+)
+
+// TrustStore holds the root hashes that an operator pre-attested, so that proofs
+// against them are not verified (eg: during a controlled migration). Each bypass
+// is logged to the store logger as an audit trail. The zero value trusts no root.
+//
+// NOTE: a TrustStore is not safe for concurrent use while roots are attested.
+type TrustStore struct {
+	logger   log.Logger
+	attested map[string]string // hex encoded root hash -> attestation
+}
+
+// NewTrustStore creates a new TrustStore with no trusted root, logging bypasses
+// to the given logger.
+func NewTrustStore(logger log.Logger) TrustStore {
+	if logger == nil {
+		logger = log.NewNopLogger()
+	}
+
+	return TrustStore{
+		logger:   logger,
+		attested: make(map[string]string),
+	}
+}
+
+// Attest marks the root hash as trusted, with the attestation recorded in the log
+// of each bypass (eg: the migration or operator that attested the root).
+func (ts TrustStore) Attest(rootHash []byte, attestation string) error {
+	if ts.attested == nil {
+		return errors.New("trust store must be created with NewTrustStore")
+	}
+	if len(rootHash) == 0 {
+		return errors.New("root hash cannot be empty")
+	}
+
+	ts.attested[fmt.Sprintf("%X", rootHash)] = attestation
+	return nil
+}
+
+// Attestation returns the attestation of the root hash and true if it is trusted.
+func (ts TrustStore) Attestation(rootHash []byte) (string, bool) {
+	attestation, ok := ts.attested[fmt.Sprintf("%X", rootHash)]
+	return attestation, ok
+}
+
+// VerifyMembershipTrusted verifies the membership of a merkle proof against the
+// given root, path and value, as VerifyMembership, unless the root was attested
+// in the trust store: the verification is then skipped and logged.
+func (proof MerkleProof) VerifyMembershipTrusted(root exported.Root, path exported.Path, value []byte, trustStore TrustStore) error {
+	if root == nil || root.IsEmpty() || path == nil || path.IsEmpty() || len(value) == 0 {
+		return errors.New("empty params or proof")
+	}
+
+	attestation, ok := trustStore.Attestation(root.GetHash())
+	if !ok {
+		return proof.VerifyMembership(root, path, value)
+	}
+
+	trustStore.logger.Info(
+		"skipped membership proof verification for trusted root",
+		"root", fmt.Sprintf("%X", root.GetHash()), "path", path.String(), "attestation", attestation,
+	)
+	return nil
+}
@@ -0,0 +1,80 @@
+package types_test
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/libs/log"
+
+	"github.com/cosmos/cosmos-sdk/x/ibc/23-commitment/types"
+)
+
+func (suite *MerkleTestSuite) TestVerifyMembershipTrusted() {
+	suite.iavlStore.Set([]byte("MYKEY"), []byte("MYVALUE"))
+	cid := suite.store.Commit()
+
+	res := suite.store.Query(abci.RequestQuery{
+		Path:  fmt.Sprintf("/%s/key", suite.storeKey.Name()), // required path to get key/value+proof
+		Data:  []byte("MYKEY"),
+		Prove: true,
+	})
+	suite.Require().NotNil(res.Proof)
+
+	proof := types.MerkleProof{Proof: res.Proof}
+	root := types.NewMerkleRoot(cid.Hash)
+	attestedRoot := types.NewMerkleRoot([]byte("migratedroot"))
+	path := types.NewMerklePath([]string{suite.storeKey.Name(), "MYKEY"})
+
+	var buf bytes.Buffer
+	trustStore := types.NewTrustStore(log.NewTMLogger(log.NewSyncWriter(&buf)))
+	suite.Require().NoError(trustStore.Attest(attestedRoot.GetHash(), "migration v2"))
+
+	testCases := []struct {
+		name       string
+		trustStore types.TrustStore
+		proof      types.MerkleProof
+		root       types.MerkleRoot
+		value      []byte
+		expPass    bool
+		expBypass  bool
+	}{
+		{"no trusted roots, valid proof", types.TrustStore{}, proof, root, []byte("MYVALUE"), true, false},
+		{"no trusted roots, invalid value", types.TrustStore{}, proof, root, []byte("WRONGVALUE"), false, false},
+		{"no trusted roots, attested root", types.TrustStore{}, proof, attestedRoot, []byte("MYVALUE"), false, false},
+		{"untrusted root, valid proof", trustStore, proof, root, []byte("MYVALUE"), true, false},
+		{"untrusted root, invalid value", trustStore, proof, root, []byte("WRONGVALUE"), false, false},
+		{"trusted root", trustStore, proof, attestedRoot, []byte("MYVALUE"), true, true},
+		{"trusted root, empty proof", trustStore, types.MerkleProof{}, attestedRoot, []byte("MYVALUE"), true, true},
+		{"trusted root, empty value", trustStore, proof, attestedRoot, nil, false, false},
+	}
+
+	for i, tc := range testCases {
+		buf.Reset()
+
+		err := tc.proof.VerifyMembershipTrusted(&tc.root, path, tc.value, tc.trustStore)
+		if tc.expPass {
+			suite.Require().NoError(err, "test case %d: %s", i, tc.name)
+		} else {
+			suite.Require().Error(err, "test case %d: %s", i, tc.name)
+		}
+
+		if tc.expBypass {
+			suite.Require().Equal(1, strings.Count(buf.String(), "skipped membership proof verification"), "test case %d: %s", i, tc.name)
+			suite.Require().Contains(buf.String(), fmt.Sprintf("root=%X", attestedRoot.GetHash()), "test case %d: %s", i, tc.name)
+			suite.Require().Contains(buf.String(), "attestation=\"migration v2\"", "test case %d: %s", i, tc.name)
+		} else {
+			suite.Require().Empty(buf.String(), "test case %d: %s", i, tc.name)
+		}
+	}
+
+	attestation, ok := trustStore.Attestation(attestedRoot.GetHash())
+	suite.Require().True(ok)
+	suite.Require().Equal("migration v2", attestation)
+	_, ok = trustStore.Attestation(root.GetHash())
+	suite.Require().False(ok)
+
+	suite.Require().Error(trustStore.Attest(nil, "empty"))
+	suite.Require().Error(types.TrustStore{}.Attest(root.GetHash(), "zero value"))
+}
@@ -0,0 +1,32 @@
+package types
+
+import (
+	"github.com/cosmos/cosmos-sdk/x/ibc/23-commitment/exported"
+)
+
+var _ exported.Root = VersionedRoot{}
+
+// VersionedRoot defines a merkle root tagged with the app version whose app hash
+// algorithm computed it, so that a light client tracking a chain across an
+// upgrade can tell how a root must be verified. The hash and the commitment type
+// are the ones of the embedded MerkleRoot.
+//
+// NOTE: the binary encoding promoted from MerkleRoot doesn't include the app
+// version.
+type VersionedRoot struct {
+	MerkleRoot
+	AppVersion uint64
+}
+
+// NewVersionedRoot constructs a new VersionedRoot
+func NewVersionedRoot(hash []byte, appVersion uint64) VersionedRoot {
+	return VersionedRoot{
+		MerkleRoot: NewMerkleRoot(hash),
+		AppVersion: appVersion,
+	}
+}
+
+// GetAppVersion returns the app version that computed the root
+func (vr VersionedRoot) GetAppVersion() uint64 {
+	return vr.AppVersion
+}
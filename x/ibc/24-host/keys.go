@@ -20,8 +20,9 @@ const (
 
 // KVStore key prefixes for IBC
 var (
-	KeyClientStorePrefix = []byte("clients")
-	KeyConnectionPrefix  = []byte("connections")
+	KeyClientStorePrefix        = []byte("clients")
+	KeyConnectionPrefix         = []byte("connections")
+	KeyConnectionMetadataPrefix = []byte("connectionMetadata")
 )
 
 // KVStore key prefixes for IBC
@@ -90,6 +91,12 @@ func ConnectionPath(connectionID string) string {
 	return fmt.Sprintf("connections/%s", connectionID)
 }
 
+// ConnectionMetadataPath defines the path under which the metadata of the
+// handshake message that created a connection is stored
+func ConnectionMetadataPath(connectionID string) string {
+	return fmt.Sprintf("%s/%s", KeyConnectionMetadataPrefix, connectionID)
+}
+
 // KeyClientConnections returns the store key for the connectios of a given client
 func KeyClientConnections(clientID string) []byte {
 	return []byte(ClientConnectionsPath(clientID))
@@ -100,6 +107,12 @@ func KeyConnection(connectionID string) []byte {
 	return []byte(ConnectionPath(connectionID))
 }
 
+// KeyConnectionMetadata returns the store key for the metadata of the handshake
+// message that created a connection
+func KeyConnectionMetadata(connectionID string) []byte {
+	return []byte(ConnectionMetadataPath(connectionID))
+}
+
 // ICS04
 // The following paths are the keys to the store as defined in https://github.com/cosmos/ics/tree/master/spec/ics-004-channel-and-packet-semantics#store-paths
 